@@ -0,0 +1,235 @@
+package analytics
+
+import (
+	"errors"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"math"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrNoTarget - Returned when a probability is requested without naming any cards or a category to draw
+	ErrNoTarget = errors.New("analytics: no cards or category were requested")
+
+	// ErrEmptyLibrary - Returned when a probability is requested for a deck without a main board
+	ErrEmptyLibrary = errors.New("analytics: the main board of the deck is empty")
+)
+
+const (
+	// HandSize - The amount of cards in an opening hand
+	HandSize = 7
+
+	// MaxTurns - The maximum amount of turns that probabilities can be requested for
+	MaxTurns = 20
+)
+
+/*
+Target - The cards that a probability is computed for. A card is a target if it is named in Cards, or if it
+belongs to Category
+*/
+type Target struct {
+	// Cards - The names of the target cards
+	Cards []string `json:"cards,omitempty"`
+
+	// Category - A category of target cards. This is either land, nonland, or a card type or subtype (ex.
+	// creature or goblin)
+	Category string `json:"category,omitempty"`
+}
+
+/*
+IsEmpty Return true if the target does not name any cards or a category
+*/
+func (target Target) IsEmpty() bool {
+	return len(target.Cards) == 0 && target.Category == ""
+}
+
+/*
+Matches Return true if a card is one of the target cards. Names are compared ignoring case, and the front
+face of a card with multiple faces also matches
+*/
+func (target Target) Matches(card *cardModel.CardDeck) bool {
+	for _, name := range target.Cards {
+		front, _, _ := strings.Cut(card.Name, " // ")
+		if strings.EqualFold(card.Name, name) || strings.EqualFold(front, name) {
+			return true
+		}
+	}
+
+	category := strings.ToLower(target.Category)
+	switch category {
+	case "":
+		return false
+	case "nonland":
+		return !slices.Contains(card.Types, "Land")
+	}
+
+	for _, cardType := range slices.Concat(card.Types, card.Subtypes) {
+		if strings.ToLower(cardType) == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+library Return the cards of the main board, with one element for each copy of a card
+*/
+func library(contents *deckModel.DeckContents) []*cardModel.CardDeck {
+	var ret []*cardModel.CardDeck
+	for _, card := range contents.MainBoard {
+		for i := int64(0); i < card.Count; i++ {
+			ret = append(ret, card)
+		}
+	}
+
+	return ret
+}
+
+/*
+logChoose Return the natural logarithm of the binomial coefficient n choose k
+*/
+func logChoose(n int64, k int64) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+
+	return a - b - c
+}
+
+/*
+Hypergeometric Return the probability of drawing at least atLeast successes when drawing draws cards without
+replacement from a population containing successes successes
+*/
+func Hypergeometric(population int64, successes int64, draws int64, atLeast int64) float64 {
+	if atLeast <= 0 {
+		return 1
+	}
+
+	draws = min(draws, population)
+	if atLeast > min(successes, draws) {
+		return 0
+	}
+
+	total := logChoose(population, draws)
+
+	var probability float64
+	for k := atLeast; k <= min(successes, draws); k++ {
+		if draws-k > population-successes {
+			continue
+		}
+
+		probability += math.Exp(logChoose(successes, k) + logChoose(population-successes, draws-k) - total)
+	}
+
+	return min(probability, 1)
+}
+
+/*
+DrawOptions - The parameters of a draw probability
+*/
+type DrawOptions struct {
+	Target
+
+	// Copies - The minimum amount of target cards that must be drawn. Defaults to 1
+	Copies int64
+
+	// Turns - The amount of turns to compute probabilities for. Defaults to 1
+	Turns int
+
+	// OnTheDraw - Set to true if a card is drawn on the first turn
+	OnTheDraw bool
+}
+
+/*
+TurnProbability - The probability of having drawn the target cards by a single turn
+*/
+type TurnProbability struct {
+	// Turn - The turn, counted from one
+	Turn int `json:"turn"`
+
+	// CardsSeen - The amount of cards drawn by the turn, including the opening hand
+	CardsSeen int64 `json:"cardsSeen"`
+
+	// Probability - The probability of having drawn the target cards, between 0 and 1
+	Probability float64 `json:"probability"`
+}
+
+/*
+DrawResult - The probability of drawing the target cards by each turn
+*/
+type DrawResult struct {
+	Target
+
+	// DeckSize - The amount of cards in the main board
+	DeckSize int64 `json:"deckSize"`
+
+	// Matching - The amount of cards in the main board that are targets
+	Matching int64 `json:"matching"`
+
+	// Copies - The minimum amount of target cards that must be drawn
+	Copies int64 `json:"copies"`
+
+	// OnTheDraw - Whether a card is drawn on the first turn
+	OnTheDraw bool `json:"onTheDraw"`
+
+	// Turns - The probability of drawing the target cards by each turn
+	Turns []*TurnProbability `json:"turns"`
+}
+
+/*
+DrawProbability Compute the probability of drawing at least the requested amount of target cards by each turn,
+without mulligans. Returns ErrNoTarget if the options do not name a target, or ErrEmptyLibrary if the main
+board of the deck is empty
+*/
+func DrawProbability(contents *deckModel.DeckContents, opts DrawOptions) (*DrawResult, error) {
+	if opts.Target.IsEmpty() {
+		return nil, ErrNoTarget
+	}
+
+	if opts.Copies <= 0 {
+		opts.Copies = 1
+	}
+
+	if opts.Turns <= 0 {
+		opts.Turns = 1
+	}
+	opts.Turns = min(opts.Turns, MaxTurns)
+
+	cards := library(contents)
+	if len(cards) == 0 {
+		return nil, ErrEmptyLibrary
+	}
+
+	result := &DrawResult{
+		Target:    opts.Target,
+		DeckSize:  int64(len(cards)),
+		Copies:    opts.Copies,
+		OnTheDraw: opts.OnTheDraw,
+		Turns:     []*TurnProbability{},
+	}
+
+	for _, card := range contents.MainBoard {
+		if opts.Target.Matches(card) {
+			result.Matching += card.Count
+		}
+	}
+
+	for turn := 1; turn <= opts.Turns; turn++ {
+		seen := int64(HandSize + turn - 1)
+		if opts.OnTheDraw {
+			seen++
+		}
+		seen = min(seen, result.DeckSize)
+
+		result.Turns = append(result.Turns, &TurnProbability{
+			Turn:        turn,
+			CardsSeen:   seen,
+			Probability: math.Round(Hypergeometric(result.DeckSize, result.Matching, seen, opts.Copies)*10000) / 10000,
+		})
+	}
+
+	return result, nil
+}
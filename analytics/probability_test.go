@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"errors"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"math"
+	"testing"
+)
+
+/*
+testDeck Return a 60 card main board with 4 copies of Lightning Bolt, 24 Mountains and 32 other creatures
+*/
+func testDeck() *deckModel.DeckContents {
+	return &deckModel.DeckContents{
+		MainBoard: []*cardModel.CardDeck{
+			{Name: "Lightning Bolt", Count: 4, Types: []string{"Instant"}, ManaValue: 1},
+			{Name: "Mountain", Count: 24, Types: []string{"Land"}, Supertypes: []string{"Basic"}, Subtypes: []string{"Mountain"}},
+			{Name: "Goblin Guide", Count: 16, Types: []string{"Creature"}, Subtypes: []string{"Goblin", "Scout"}, ManaValue: 1},
+			{Name: "Bonecrusher Giant // Stomp", Count: 16, Types: []string{"Creature"}, Subtypes: []string{"Giant"}, ManaValue: 3},
+		},
+	}
+}
+
+func TestHypergeometric(t *testing.T) {
+	tests := []struct {
+		name       string
+		population int64
+		successes  int64
+		draws      int64
+		atLeast    int64
+		want       float64
+	}{
+		{"4-of in an opening hand", 60, 4, 7, 1, 0.399500},
+		{"4-of by the first draw", 60, 4, 8, 1, 0.444820},
+		{"two copies of a 4-of", 60, 4, 7, 2, 0.063219},
+		{"3 of 24 lands", 60, 24, 7, 3, 0.587929},
+		{"singleton in 99", 99, 1, 7, 1, 0.070707},
+		{"2 of 17 lands in limited", 40, 17, 7, 2, 0.894802},
+		{"whole library", 60, 4, 60, 4, 1},
+		{"more draws than the population", 10, 4, 20, 4, 1},
+		{"at least zero", 60, 0, 7, 0, 1},
+		{"no successes", 60, 0, 7, 1, 0},
+		{"more copies than exist", 60, 4, 7, 5, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Hypergeometric(test.population, test.successes, test.draws, test.atLeast)
+			if math.Abs(got-test.want) > 1e-6 {
+				t.Errorf("Hypergeometric(%d, %d, %d, %d) = %f, want %f", test.population, test.successes, test.draws, test.atLeast, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTargetMatches(t *testing.T) {
+	deck := testDeck()
+	bolt, mountain, guide, giant := deck.MainBoard[0], deck.MainBoard[1], deck.MainBoard[2], deck.MainBoard[3]
+
+	tests := []struct {
+		name   string
+		target Target
+		card   *cardModel.CardDeck
+		want   bool
+	}{
+		{"name", Target{Cards: []string{"Lightning Bolt"}}, bolt, true},
+		{"name ignoring case", Target{Cards: []string{"lightning bolt"}}, bolt, true},
+		{"front face", Target{Cards: []string{"Bonecrusher Giant"}}, giant, true},
+		{"full name", Target{Cards: []string{"Bonecrusher Giant // Stomp"}}, giant, true},
+		{"back face", Target{Cards: []string{"Stomp"}}, giant, false},
+		{"other name", Target{Cards: []string{"Shock"}}, bolt, false},
+		{"land", Target{Category: "land"}, mountain, true},
+		{"nonland", Target{Category: "nonland"}, bolt, true},
+		{"nonland excludes lands", Target{Category: "nonland"}, mountain, false},
+		{"type", Target{Category: "Creature"}, guide, true},
+		{"subtype", Target{Category: "goblin"}, guide, true},
+		{"other subtype", Target{Category: "goblin"}, giant, false},
+		{"empty", Target{}, bolt, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.target.Matches(test.card); got != test.want {
+				t.Errorf("Matches(%s) = %v, want %v", test.card.Name, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDrawProbability(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     DrawOptions
+		matching int64
+		want     []float64
+	}{
+		{"on the play", DrawOptions{Target: Target{Cards: []string{"Lightning Bolt"}}, Turns: 3}, 4, []float64{0.3995, 0.4448, 0.4875}},
+		{"on the draw", DrawOptions{Target: Target{Cards: []string{"Lightning Bolt"}}, Turns: 3, OnTheDraw: true}, 4, []float64{0.4448, 0.4875, 0.5277}},
+		{"defaults to one turn", DrawOptions{Target: Target{Cards: []string{"Lightning Bolt"}}}, 4, []float64{0.3995}},
+		{"copies", DrawOptions{Target: Target{Cards: []string{"Lightning Bolt"}}, Copies: 2}, 4, []float64{0.0632}},
+		{"category", DrawOptions{Target: Target{Category: "land"}, Copies: 3}, 24, []float64{0.5879}},
+		{"cards and category", DrawOptions{Target: Target{Cards: []string{"Lightning Bolt"}, Category: "land"}, Copies: 3}, 28, []float64{0.7285}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := DrawProbability(testDeck(), test.opts)
+			if err != nil {
+				t.Fatalf("DrawProbability() returned an error: %v", err)
+			}
+
+			if result.DeckSize != 60 || result.Matching != test.matching {
+				t.Errorf("DrawProbability() found %d of %d cards, want %d of 60", result.Matching, result.DeckSize, test.matching)
+			}
+
+			if len(result.Turns) != len(test.want) {
+				t.Fatalf("DrawProbability() returned %d turns, want %d", len(result.Turns), len(test.want))
+			}
+
+			for i, turn := range result.Turns {
+				if turn.Turn != i+1 || turn.Probability != test.want[i] {
+					t.Errorf("turn %d = %v, want turn %d = %v", turn.Turn, turn.Probability, i+1, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDrawProbabilityLimits(t *testing.T) {
+	result, err := DrawProbability(testDeck(), DrawOptions{Target: Target{Category: "land"}, Turns: MaxTurns + 10})
+	if err != nil {
+		t.Fatalf("DrawProbability() returned an error: %v", err)
+	}
+
+	if len(result.Turns) != MaxTurns {
+		t.Errorf("DrawProbability() returned %d turns, want %d", len(result.Turns), MaxTurns)
+	}
+
+	small := &deckModel.DeckContents{MainBoard: []*cardModel.CardDeck{{Name: "Mountain", Count: 5, Types: []string{"Land"}}}}
+	result, err = DrawProbability(small, DrawOptions{Target: Target{Category: "land"}, Copies: 5})
+	if err != nil {
+		t.Fatalf("DrawProbability() returned an error: %v", err)
+	}
+
+	if turn := result.Turns[0]; turn.CardsSeen != 5 || turn.Probability != 1 {
+		t.Errorf("expected the whole library to be seen, got %d cards at %v", turn.CardsSeen, turn.Probability)
+	}
+
+	if _, err := DrawProbability(testDeck(), DrawOptions{}); !errors.Is(err, ErrNoTarget) {
+		t.Errorf("DrawProbability() returned %v, want %v", err, ErrNoTarget)
+	}
+
+	if _, err := DrawProbability(&deckModel.DeckContents{}, DrawOptions{Target: Target{Category: "land"}}); !errors.Is(err, ErrEmptyLibrary) {
+		t.Errorf("DrawProbability() returned %v, want %v", err, ErrEmptyLibrary)
+	}
+}
@@ -0,0 +1,223 @@
+package analytics
+
+import (
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+const (
+	// DefaultTrials - The amount of opening hands simulated if an amount is not requested
+	DefaultTrials = 10000
+
+	// MaxTrials - The maximum amount of opening hands that can be simulated at once
+	MaxTrials = 100000
+)
+
+/*
+SimulationOptions - The parameters of an opening hand simulation
+*/
+type SimulationOptions struct {
+	// Target - If set, then the share of kept hands containing a target card is reported
+	Target Target
+
+	// RequireTarget - Set to true if hands without a target card are mulliganed
+	RequireTarget bool
+
+	// Trials - The amount of opening hands to simulate. Defaults to DefaultTrials
+	Trials int
+
+	// Seed - The seed of the random number generator. Simulations with the same seed and options always
+	// produce the same result
+	Seed uint64
+
+	// MinLands - The minimum amount of lands in a seven card hand for it to be kept. Defaults to 2
+	MinLands int
+
+	// MaxLands - The maximum amount of lands in a seven card hand for it to be kept. Defaults to 5
+	MaxLands int
+
+	// MinHandSize - The hand size that is always kept, rather than mulliganed again. Defaults to 5
+	MinHandSize int
+}
+
+/*
+SimulationResult - The outcome of simulating many opening hands
+*/
+type SimulationResult struct {
+	// Seed - The seed that the simulation was run with
+	Seed uint64 `json:"seed"`
+
+	// Trials - The amount of opening hands that were simulated
+	Trials int `json:"trials"`
+
+	// Mulligans - The share of trials that kept a hand after each amount of mulligans, keyed by the amount
+	Mulligans map[string]float64 `json:"mulligans"`
+
+	// AverageHandSize - The average size of a kept hand
+	AverageHandSize float64 `json:"averageHandSize"`
+
+	// AverageLands - The average amount of lands in a kept hand
+	AverageLands float64 `json:"averageLands"`
+
+	// Lands - The share of kept hands with each amount of lands, keyed by the amount
+	Lands map[string]float64 `json:"lands"`
+
+	// TargetRate - The share of kept hands containing at least one target card. This is omitted if no target
+	// was requested
+	TargetRate *float64 `json:"targetRate,omitempty"`
+}
+
+/*
+isLand Return true if a card is a land
+*/
+func isLand(card *cardModel.CardDeck) bool {
+	return slices.Contains(card.Types, "Land")
+}
+
+/*
+countLands Return the amount of lands in a hand
+*/
+func countLands(hand []*cardModel.CardDeck) int {
+	var lands int
+	for _, card := range hand {
+		if isLand(card) {
+			lands++
+		}
+	}
+
+	return lands
+}
+
+/*
+bottom Put cards from a seven card hand on the bottom of the library, as is done by the London mulligan, and
+return the hand that is kept. Lands are bottomed while they make up more than half of the hand and there
+are more than the minimum, otherwise the spell with the highest mana value is bottomed
+*/
+func bottom(hand []*cardModel.CardDeck, amount int, minLands int) []*cardModel.CardDeck {
+	hand = slices.Clone(hand)
+
+	for i := 0; i < amount && len(hand) != 0; i++ {
+		lands := countLands(hand)
+
+		pick := -1
+		if lands == len(hand) || (lands > minLands && lands*2 > len(hand)) {
+			pick = slices.IndexFunc(hand, isLand)
+		} else {
+			for j, card := range hand {
+				if isLand(card) {
+					continue
+				}
+
+				if pick == -1 || card.ManaValue > hand[pick].ManaValue {
+					pick = j
+				}
+			}
+		}
+
+		hand = slices.Delete(hand, pick, pick+1)
+	}
+
+	return hand
+}
+
+/*
+Simulate Simulate opening hands using the London mulligan. A seven card hand is drawn and kept if it has
+between MinLands and MaxLands lands (and a target card, if RequireTarget is set). Otherwise the library is
+shuffled and seven new cards are drawn, with one more card put on the bottom for each mulligan taken. A hand
+that would be reduced to MinHandSize is always kept. Returns ErrEmptyLibrary if the main board of the deck
+is empty
+*/
+func Simulate(contents *deckModel.DeckContents, opts SimulationOptions) (*SimulationResult, error) {
+	if opts.Trials <= 0 {
+		opts.Trials = DefaultTrials
+	}
+	opts.Trials = min(opts.Trials, MaxTrials)
+
+	if opts.MinLands <= 0 && opts.MaxLands <= 0 {
+		opts.MinLands, opts.MaxLands = 2, 5
+	}
+
+	if opts.MaxLands <= 0 {
+		opts.MaxLands = HandSize
+	}
+
+	if opts.MinHandSize <= 0 || opts.MinHandSize > HandSize {
+		opts.MinHandSize = 5
+	}
+
+	cards := library(contents)
+	if len(cards) == 0 {
+		return nil, ErrEmptyLibrary
+	}
+
+	random := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+
+	mulligans := make(map[int]int)
+	lands := make(map[int]int)
+	var totalHandSize, totalLands, withTarget int
+
+	for trial := 0; trial < opts.Trials; trial++ {
+		var kept []*cardModel.CardDeck
+
+		for taken := 0; ; taken++ {
+			random.Shuffle(len(cards), func(i, j int) {
+				cards[i], cards[j] = cards[j], cards[i]
+			})
+
+			hand := cards[:min(HandSize, len(cards))]
+
+			handLands := countLands(hand)
+			keep := handLands >= opts.MinLands && handLands <= opts.MaxLands
+			if keep && opts.RequireTarget {
+				keep = slices.ContainsFunc(hand, opts.Target.Matches)
+			}
+
+			if keep || HandSize-taken <= opts.MinHandSize {
+				kept = bottom(hand, taken, opts.MinLands)
+				mulligans[taken]++
+				break
+			}
+		}
+
+		keptLands := countLands(kept)
+		lands[keptLands]++
+		totalHandSize += len(kept)
+		totalLands += keptLands
+
+		if !opts.Target.IsEmpty() && slices.ContainsFunc(kept, opts.Target.Matches) {
+			withTarget++
+		}
+	}
+
+	share := func(count int) float64 {
+		return math.Round(float64(count)/float64(opts.Trials)*10000) / 10000
+	}
+
+	result := &SimulationResult{
+		Seed:            opts.Seed,
+		Trials:          opts.Trials,
+		Mulligans:       make(map[string]float64),
+		AverageHandSize: math.Round(float64(totalHandSize)/float64(opts.Trials)*100) / 100,
+		AverageLands:    math.Round(float64(totalLands)/float64(opts.Trials)*100) / 100,
+		Lands:           make(map[string]float64),
+	}
+
+	for taken, count := range mulligans {
+		result.Mulligans[strconv.Itoa(taken)] = share(count)
+	}
+
+	for amount, count := range lands {
+		result.Lands[strconv.Itoa(amount)] = share(count)
+	}
+
+	if !opts.Target.IsEmpty() {
+		rate := share(withTarget)
+		result.TargetRate = &rate
+	}
+
+	return result, nil
+}
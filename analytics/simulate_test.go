@@ -0,0 +1,150 @@
+package analytics
+
+import (
+	"errors"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	opts := SimulationOptions{Target: Target{Cards: []string{"Lightning Bolt"}}, Trials: 2000, Seed: 42}
+
+	first, err := Simulate(testDeck(), opts)
+	if err != nil {
+		t.Fatalf("Simulate() returned an error: %v", err)
+	}
+
+	second, err := Simulate(testDeck(), opts)
+	if err != nil {
+		t.Fatalf("Simulate() returned an error: %v", err)
+	}
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Simulate() with the same seed returned different results:\n%+v\n%+v", first, second)
+	}
+
+	opts.Seed = 43
+	other, err := Simulate(testDeck(), opts)
+	if err != nil {
+		t.Fatalf("Simulate() returned an error: %v", err)
+	}
+
+	if reflect.DeepEqual(first, other) {
+		t.Error("Simulate() with a different seed returned the same result")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	result, err := Simulate(testDeck(), SimulationOptions{Target: Target{Cards: []string{"Lightning Bolt"}}, Trials: 5000, Seed: 1})
+	if err != nil {
+		t.Fatalf("Simulate() returned an error: %v", err)
+	}
+
+	if result.Seed != 1 || result.Trials != 5000 {
+		t.Errorf("Simulate() ran %d trials with seed %d, want 5000 with seed 1", result.Trials, result.Seed)
+	}
+
+	var mulligans float64
+	for _, share := range result.Mulligans {
+		mulligans += share
+	}
+
+	if mulligans < 0.999 || mulligans > 1.001 {
+		t.Errorf("mulligan shares sum to %v, want 1", mulligans)
+	}
+
+	if result.Mulligans["0"] < 0.8 {
+		t.Errorf("expected most hands of a 24 land deck to be kept, got %v", result.Mulligans["0"])
+	}
+
+	if result.AverageHandSize <= 6 || result.AverageHandSize > HandSize {
+		t.Errorf("AverageHandSize = %v, want between 6 and 7", result.AverageHandSize)
+	}
+
+	if result.AverageLands < 2 || result.AverageLands > 5 {
+		t.Errorf("AverageLands = %v, want between 2 and 5", result.AverageLands)
+	}
+
+	if result.TargetRate == nil || *result.TargetRate < 0.3 || *result.TargetRate > 0.5 {
+		t.Errorf("TargetRate = %v, want close to the drawn probability of 0.3995", result.TargetRate)
+	}
+}
+
+func TestSimulateMulligans(t *testing.T) {
+	lands := &deckModel.DeckContents{MainBoard: []*cardModel.CardDeck{{Name: "Mountain", Count: 60, Types: []string{"Land"}}}}
+	spells := &deckModel.DeckContents{MainBoard: []*cardModel.CardDeck{
+		{Name: "Lightning Bolt", Count: 30, Types: []string{"Instant"}, ManaValue: 1},
+		{Name: "Fireball", Count: 30, Types: []string{"Sorcery"}, ManaValue: 5},
+	}}
+
+	tests := []struct {
+		name      string
+		contents  *deckModel.DeckContents
+		opts      SimulationOptions
+		mulligans map[string]float64
+		handSize  float64
+		lands     map[string]float64
+	}{
+		{"too many lands", lands, SimulationOptions{Trials: 100}, map[string]float64{"2": 1}, 5, map[string]float64{"5": 1}},
+		{"no lands", spells, SimulationOptions{Trials: 100}, map[string]float64{"2": 1}, 5, map[string]float64{"0": 1}},
+		{"keep any hand", spells, SimulationOptions{Trials: 100, MaxLands: 7}, map[string]float64{"0": 1}, 7, map[string]float64{"0": 1}},
+		{"minimum hand size", lands, SimulationOptions{Trials: 100, MinHandSize: 3}, map[string]float64{"4": 1}, 3, map[string]float64{"3": 1}},
+		{"missing target", lands, SimulationOptions{Trials: 100, MaxLands: 7, Target: Target{Category: "nonland"}, RequireTarget: true}, map[string]float64{"2": 1}, 5, map[string]float64{"5": 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Simulate(test.contents, test.opts)
+			if err != nil {
+				t.Fatalf("Simulate() returned an error: %v", err)
+			}
+
+			if !reflect.DeepEqual(result.Mulligans, test.mulligans) {
+				t.Errorf("Mulligans = %v, want %v", result.Mulligans, test.mulligans)
+			}
+
+			if result.AverageHandSize != test.handSize {
+				t.Errorf("AverageHandSize = %v, want %v", result.AverageHandSize, test.handSize)
+			}
+
+			if !reflect.DeepEqual(result.Lands, test.lands) {
+				t.Errorf("Lands = %v, want %v", result.Lands, test.lands)
+			}
+		})
+	}
+}
+
+func TestBottom(t *testing.T) {
+	land := &cardModel.CardDeck{Name: "Mountain", Types: []string{"Land"}}
+	bolt := &cardModel.CardDeck{Name: "Lightning Bolt", Types: []string{"Instant"}, ManaValue: 1}
+	fireball := &cardModel.CardDeck{Name: "Fireball", Types: []string{"Sorcery"}, ManaValue: 5}
+
+	tests := []struct {
+		name   string
+		hand   []*cardModel.CardDeck
+		amount int
+		want   []*cardModel.CardDeck
+	}{
+		{"nothing", []*cardModel.CardDeck{land, bolt}, 0, []*cardModel.CardDeck{land, bolt}},
+		{"highest mana value spell", []*cardModel.CardDeck{land, land, bolt, fireball, bolt}, 1, []*cardModel.CardDeck{land, land, bolt, bolt}},
+		{"excess lands", []*cardModel.CardDeck{land, land, land, land, bolt, fireball, bolt}, 2, []*cardModel.CardDeck{land, land, land, bolt, bolt}},
+		{"keeps minimum lands", []*cardModel.CardDeck{land, land, bolt}, 1, []*cardModel.CardDeck{land, land}},
+		{"only lands", []*cardModel.CardDeck{land, land, land}, 2, []*cardModel.CardDeck{land}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := bottom(test.hand, test.amount, 2); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("bottom() kept %d cards, want %d", len(got), len(test.want))
+			}
+		})
+	}
+}
+
+func TestSimulateEmptyLibrary(t *testing.T) {
+	if _, err := Simulate(&deckModel.DeckContents{}, SimulationOptions{}); !errors.Is(err, ErrEmptyLibrary) {
+		t.Errorf("Simulate() returned %v, want %v", err, ErrEmptyLibrary)
+	}
+}
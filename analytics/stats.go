@@ -0,0 +1,286 @@
+package analytics
+
+import (
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+colors - The colors that mana costs and mana sources are counted for, in WUBRG order followed by colorless
+*/
+var colors = []string{"W", "U", "B", "R", "G", "C"}
+
+/*
+basicLandTypes - Maps each basic land type to the color of mana it produces
+*/
+var basicLandTypes = map[string]string{
+	"Plains":   "W",
+	"Island":   "U",
+	"Swamp":    "B",
+	"Mountain": "R",
+	"Forest":   "G",
+	"Wastes":   "C",
+}
+
+/*
+symbolPattern - Matches a single mana symbol in a mana cost or rules text (ex. {2}, {W/U} or {G/P})
+*/
+var symbolPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+/*
+addPattern - Matches a mana ability that adds mana, up to the end of its sentence (ex. Add {R} or {G}.)
+*/
+var addPattern = regexp.MustCompile(`Add ([^.]*)`)
+
+/*
+ColorBalance - The mana requirements of a single color compared to the sources that produce it
+*/
+type ColorBalance struct {
+	// Color - The color (ex. W). Colorless mana is represented by C
+	Color string `json:"color"`
+
+	// Pips - The amount of mana symbols of this color across the mana costs of every card
+	Pips int64 `json:"pips"`
+
+	// PipShare - The share of every colored pip that is of this color, between 0 and 1
+	PipShare float64 `json:"pipShare"`
+
+	// Sources - The amount of cards that can produce mana of this color
+	Sources int64 `json:"sources"`
+
+	// SourceShare - The share of this color among the colors produced by every mana source, between 0 and 1
+	SourceShare float64 `json:"sourceShare"`
+}
+
+/*
+Stats - Statistics computed from the contents of a deck. Apart from the mana curve, which is broken down by
+board, every statistic is computed from the main board and the commander
+*/
+type Stats struct {
+	// Cards - The amount of cards in the main board and commander
+	Cards int64 `json:"cards"`
+
+	// Curve - The amount of non-land cards at each mana value, keyed by board and then by mana value. Cards with
+	// a mana value of 7 or more are grouped under 7+
+	Curve map[string]map[string]int64 `json:"curve"`
+
+	// Types - The amount of cards of each card type. Cards with multiple types are counted once for each type
+	Types map[string]int64 `json:"types"`
+
+	// Lands - The amount of lands
+	Lands int64 `json:"lands"`
+
+	// Creatures - The amount of creatures
+	Creatures int64 `json:"creatures"`
+
+	// Spells - The amount of cards that are not lands
+	Spells int64 `json:"spells"`
+
+	// LandRatio - The share of cards that are lands, between 0 and 1
+	LandRatio float64 `json:"landRatio"`
+
+	// CreatureRatio - The share of cards that are creatures, between 0 and 1
+	CreatureRatio float64 `json:"creatureRatio"`
+
+	// SpellRatio - The share of cards that are not lands, between 0 and 1
+	SpellRatio float64 `json:"spellRatio"`
+
+	// AverageManaValue - The average mana value of every card, including lands
+	AverageManaValue float64 `json:"averageManaValue"`
+
+	// AverageManaValueNonLand - The average mana value of every card that is not a land
+	AverageManaValueNonLand float64 `json:"averageManaValueNonLand"`
+
+	// Pips - The amount of mana symbols of each color across the mana costs of every card
+	Pips map[string]int64 `json:"pips"`
+
+	// Colors - The mana requirements of each color compared to the sources that produce it
+	Colors []*ColorBalance `json:"colors"`
+}
+
+/*
+round Round a statistic to two decimal places
+*/
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+/*
+ratio Return part divided by total, rounded to two decimal places. Zero is returned if total is zero
+*/
+func ratio(part int64, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return round(float64(part) / float64(total))
+}
+
+/*
+curveBucket Return the bucket of the mana curve that a mana value falls in
+*/
+func curveBucket(manaValue float32) string {
+	value := int(manaValue)
+	if value >= 7 {
+		return "7+"
+	}
+
+	return strconv.Itoa(value)
+}
+
+/*
+Pips Count the colored mana symbols in a mana cost. Hybrid symbols count towards each of their colors,
+Phyrexian symbols count towards their color, and generic, X and snow symbols are not counted
+*/
+func Pips(manaCost string) map[string]int64 {
+	ret := make(map[string]int64)
+	for _, match := range symbolPattern.FindAllStringSubmatch(manaCost, -1) {
+		for _, part := range strings.Split(match[1], "/") {
+			if slices.Contains(colors, part) {
+				ret[part]++
+			}
+		}
+	}
+
+	return ret
+}
+
+/*
+ProducedColors Return the colors of mana that a card can produce, read from the mana abilities in its rules
+text and from its basic land types. Cards that add mana of any color produce every color
+*/
+func ProducedColors(card *cardModel.CardDeck) []string {
+	produced := make(map[string]bool)
+
+	for _, subtype := range card.Subtypes {
+		if color, ok := basicLandTypes[subtype]; ok {
+			produced[color] = true
+		}
+	}
+
+	for _, ability := range addPattern.FindAllStringSubmatch(card.Text, -1) {
+		if strings.Contains(ability[1], "any color") {
+			for _, color := range colors[:5] {
+				produced[color] = true
+			}
+		}
+
+		for _, symbol := range symbolPattern.FindAllStringSubmatch(ability[1], -1) {
+			if slices.Contains(colors, symbol[1]) {
+				produced[symbol[1]] = true
+			}
+		}
+	}
+
+	var ret []string
+	for _, color := range colors {
+		if produced[color] {
+			ret = append(ret, color)
+		}
+	}
+
+	return ret
+}
+
+/*
+Compute Compute statistics from the contents of a deck
+*/
+func Compute(contents *deckModel.DeckContents) *Stats {
+	stats := &Stats{
+		Curve: make(map[string]map[string]int64),
+		Types: make(map[string]int64),
+		Pips:  make(map[string]int64),
+	}
+
+	boards := []struct {
+		name  string
+		cards []*cardModel.CardDeck
+	}{
+		{"commander", contents.Commander},
+		{"mainBoard", contents.MainBoard},
+		{"sideBoard", contents.SideBoard},
+	}
+
+	for _, board := range boards {
+		curve := make(map[string]int64)
+		for _, card := range board.cards {
+			if !slices.Contains(card.Types, "Land") {
+				curve[curveBucket(card.ManaValue)] += card.Count
+			}
+		}
+		stats.Curve[board.name] = curve
+	}
+
+	sources := make(map[string]int64)
+	var totalManaValue, nonLandManaValue float64
+
+	for _, board := range boards[:2] {
+		for _, card := range board.cards {
+			stats.Cards += card.Count
+			totalManaValue += float64(card.ManaValue) * float64(card.Count)
+
+			for _, cardType := range card.Types {
+				stats.Types[cardType] += card.Count
+			}
+
+			if slices.Contains(card.Types, "Land") {
+				stats.Lands += card.Count
+			} else {
+				stats.Spells += card.Count
+				nonLandManaValue += float64(card.ManaValue) * float64(card.Count)
+			}
+
+			if slices.Contains(card.Types, "Creature") {
+				stats.Creatures += card.Count
+			}
+
+			for color, pips := range Pips(card.ManaCost) {
+				stats.Pips[color] += pips * card.Count
+			}
+
+			for _, color := range ProducedColors(card) {
+				sources[color] += card.Count
+			}
+		}
+	}
+
+	stats.LandRatio = ratio(stats.Lands, stats.Cards)
+	stats.CreatureRatio = ratio(stats.Creatures, stats.Cards)
+	stats.SpellRatio = ratio(stats.Spells, stats.Cards)
+
+	if stats.Cards != 0 {
+		stats.AverageManaValue = round(totalManaValue / float64(stats.Cards))
+	}
+
+	if stats.Spells != 0 {
+		stats.AverageManaValueNonLand = round(nonLandManaValue / float64(stats.Spells))
+	}
+
+	var totalPips, totalSources int64
+	for _, color := range colors {
+		totalPips += stats.Pips[color]
+		totalSources += sources[color]
+	}
+
+	stats.Colors = []*ColorBalance{}
+	for _, color := range colors {
+		if stats.Pips[color] == 0 && sources[color] == 0 {
+			continue
+		}
+
+		stats.Colors = append(stats.Colors, &ColorBalance{
+			Color:       color,
+			Pips:        stats.Pips[color],
+			PipShare:    ratio(stats.Pips[color], totalPips),
+			Sources:     sources[color],
+			SourceShare: ratio(sources[color], totalSources),
+		})
+	}
+
+	return stats
+}
@@ -1,18 +1,34 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/gin-gonic/gin"
 	sloggin "github.com/samber/slog-gin"
 	"github.com/spf13/viper"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"log/slog"
+	"mtgjson/auth"
+	"mtgjson/index"
 	"mtgjson/middleware"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 )
 
 // HandlerFunc - Wraps all handler functions to ensure that they can get passed a reference to the Server structure
 type HandlerFunc func(server *server.Server) gin.HandlerFunc
 
+// ProviderHandlerFunc - Wraps handler functions that also require a reference to the authentication provider
+type ProviderHandlerFunc func(server *server.Server, provider auth.Provider) gin.HandlerFunc
+
+// NameIndexHandlerFunc - Wraps handler functions that also require a reference to the card name index
+type NameIndexHandlerFunc func(server *server.Server, names *index.NameIndex) gin.HandlerFunc
+
 /*
 API - An abstraction of the API as a whole
 */
@@ -22,19 +38,57 @@ type API struct {
 
 	// router - The primary gin router used for routing endpoints on the API
 	router *gin.Engine
+
+	// provider - The authentication provider used for validating tokens and account operations
+	provider auth.Provider
+
+	// tokenValidator - The JWT validator that is shared across all authenticated routes
+	tokenValidator *validator.Validator
+
+	// identityCache - Caches the email address of callers whose tokens do not carry an email claim. This
+	// is nil if the cache is disabled
+	identityCache *auth.IdentityCache
+
+	// names - The in-memory index of card names used for autocompletion and fuzzy lookups. This is
+	// filled when Run is called
+	names *index.NameIndex
+
+	// httpServer - The HTTP server that serves the router. This is nil until Run is called
+	httpServer *http.Server
 }
 
 /*
-New - A constructor for the API structure
+New - A constructor for the API structure. The authentication provider and token validator are built
+here once and then shared across all requests. If the property 'auth.identity_cache_ttl' is greater than
+zero, then an identity cache is created with a TTL of that many seconds
 */
-func New(server *server.Server) *API {
+func New(server *server.Server) (*API, error) {
 	router := gin.New()
 	router.Use(gin.Recovery(), sloggin.New(server.Log().Logger()))
 
-	return &API{
-		server: server,
-		router: router,
+	provider, err := auth.NewProvider(server)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenValidator, err := auth.NewTokenValidator(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var identityCache *auth.IdentityCache
+	if ttl := viper.GetInt("auth.identity_cache_ttl"); ttl > 0 {
+		identityCache = auth.NewIdentityCache(time.Duration(ttl) * time.Second)
 	}
+
+	return &API{
+		server:         server,
+		router:         router,
+		provider:       provider,
+		tokenValidator: tokenValidator,
+		identityCache:  identityCache,
+		names:          index.NewNameIndex(),
+	}, nil
 }
 
 /*
@@ -45,7 +99,7 @@ func FromConfig() (*API, error) {
 	if err != nil {
 		return nil, err
 	}
-	return New(serv), nil
+	return New(serv)
 }
 
 /*
@@ -55,24 +109,78 @@ access the endpoint. If an empty string is provided to the scope, then one won't
 access it
 */
 func (api *API) RegisterEndpoint(method string, path string, scope string, hasAuth bool, handler HandlerFunc) {
+	api.registerRoute(api.router, Route{Method: method, Path: path, Scope: scope, HasAuth: hasAuth, Handler: handler})
+}
+
+/*
+RegisterVersion - Registers each route in the version's route table under a gin router group for
+its prefix. If the version is deprecated, then a deprecation handler is applied to the group before
+any of the version's own middleware
+*/
+func (api *API) RegisterVersion(version Version) {
+	group := api.router.Group(version.Prefix)
+
+	if version.Deprecated {
+		deprecatedAt := version.DeprecatedAt
+		if deprecatedAt.IsZero() {
+			deprecatedAt = time.Now()
+		}
+
+		group.Use(middleware.DeprecationHandler(deprecatedAt, version.Sunset, version.Successor))
+	}
+
+	group.Use(version.Middleware...)
+
+	for _, route := range version.Routes {
+		api.registerRoute(group, route)
+	}
+}
+
+/*
+withProvider - Converts a ProviderHandlerFunc into a HandlerFunc by passing it the authentication
+provider of the API
+*/
+func (api *API) withProvider(handler ProviderHandlerFunc) HandlerFunc {
+	return func(server *server.Server) gin.HandlerFunc {
+		return handler(server, api.provider)
+	}
+}
+
+/*
+withNames - Converts a NameIndexHandlerFunc into a HandlerFunc by passing it the card name index of
+the API
+*/
+func (api *API) withNames(handler NameIndexHandlerFunc) HandlerFunc {
+	return func(server *server.Server) gin.HandlerFunc {
+		return handler(server, api.names)
+	}
+}
+
+/*
+registerRoute - Builds the handler chain for a route and registers it with the passed router. A
+token validation handler is added if the route requires authentication, followed by a scope
+validation handler if the route requires a scope
+*/
+func (api *API) registerRoute(router gin.IRoutes, route Route) {
 	var handlers []gin.HandlerFunc
 
-	if hasAuth {
-		handlers = append(handlers, middleware.ValidateTokenHandler(api.server))
+	if route.HasAuth {
+		handlers = append(handlers, middleware.ValidateTokenHandler(api.server, api.provider, api.tokenValidator, api.identityCache))
 	}
 
-	if scope != "" {
-		handlers = append(handlers, middleware.ValidateScopeHandler(scope))
+	if route.Scope != "" {
+		handlers = append(handlers, middleware.ValidateScopeHandler(route.Scope))
 	}
 
-	handlers = append(handlers, handler(api.server))
+	handlers = append(handlers, route.Handler(api.server))
 
-	api.router.Handle(method, path, handlers...)
+	router.Handle(route.Method, route.Path, handlers...)
 }
 
 /*
 Run - Connect to the MongoDB database and Start the API Server. The port parameter should describe
-the port you want to expose the API on
+the port you want to expose the API on. Run blocks until a SIGINT or SIGTERM is received, at which
+point the API is gracefully shut down using Shutdown
 */
 func (api *API) Run(port int) error {
 	slog.Info("Initiating connection to MongoDB", "hostname", viper.GetString("mongo.hostname"))
@@ -82,23 +190,78 @@ func (api *API) Run(port int) error {
 		return err
 	}
 
-	slog.Info("Starting API Server", "port", port)
-	err = api.router.Run(":" + strconv.Itoa(port))
+	slog.Info("Ensuring card search indexes exist")
+	err = index.EnsureCardIndexes(api.server)
 	if err != nil {
-		slog.Error("Failed to start API Server", "err", err)
-		return err
+		slog.Warn("Failed to create card search indexes. Card searches may be slow or fail", "err", err)
 	}
 
-	return nil
+	slog.Info("Building card name index")
+	err = api.names.Load(api.server)
+	if err != nil {
+		slog.Warn("Failed to build card name index. Autocomplete and fuzzy lookups will only find cards created from now on", "err", err)
+	}
+
+	slog.Info("Fetching JWKS from issuer", "issuer", api.provider.IssuerUrl().String())
+	_, err = api.provider.KeyFunc(context.Background())
+	if err != nil {
+		slog.Warn("Failed to fetch JWKS from issuer. Keys will be fetched on the first authenticated request", "err", err)
+	}
+
+	api.httpServer = &http.Server{
+		Addr:    ":" + strconv.Itoa(port),
+		Handler: api.router,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting API Server", "port", port)
+		err := api.httpServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err = <-serverErr:
+		if err != nil {
+			slog.Error("Failed to start API Server", "err", err)
+			if disconnectErr := api.server.Database().Disconnect(); disconnectErr != nil {
+				slog.Error("Failed to disconnect from MongoDB", "err", disconnectErr)
+			}
+			return err
+		}
+	case sig := <-signals:
+		slog.Info("Received signal", "signal", sig.String())
+	}
+
+	return api.Shutdown()
 }
 
 /*
-Shutdown - Gracefully stop the API Server and then disconnect from MongoDB
+Shutdown - Gracefully stop the API Server and then disconnect from MongoDB. New connections are no
+longer accepted, and in-flight requests are given the amount of seconds defined in viper under the
+property 'api.shutdown_timeout' to complete before they are closed
 */
 func (api *API) Shutdown() error {
 	slog.Info("Shutting down API")
-	// stop receiving connections here
-	// gin router doesn't provide this natively
+
+	if api.httpServer != nil {
+		timeout := time.Duration(viper.GetInt("api.shutdown_timeout")) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		slog.Info("Draining in-flight requests", "timeout", timeout.String())
+		err := api.httpServer.Shutdown(ctx)
+		if err != nil {
+			slog.Error("Failed to drain in-flight requests before timeout", "err", err)
+		}
+	}
 
 	slog.Info("Disconnecting from MongoDB", "hostname", viper.GetString("mongo.hostname"))
 	err := api.server.Database().Disconnect()
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"net/http"
+	"time"
+)
+
+/*
+APIKeyGET Gin handler for the GET request to the API Key Endpoint. This function should not be called
+directly and should only be passed to the gin router
+*/
+func APIKeyGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		email := ctx.DefaultQuery("email", userEmail)
+
+		if !authorize(ctx, auth.ResourceUser, auth.ActionRead, email) {
+			return
+		}
+
+		keys, err := auth.IndexAPIKeys(server, email)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch API keys", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, keys)
+	}
+}
+
+/*
+APIKeyPOST Gin handler for the POST request to the API Key Endpoint. This function should not be called
+directly and should only be passed to the gin router. The raw key is only returned in this response
+*/
+func APIKeyPOST(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		type APIKeyRequest struct {
+			Name          string `json:"name"`
+			Scope         string `json:"scope"`
+			ExpiresInDays int    `json:"expiresInDays"`
+		}
+
+		var request APIKeyRequest
+
+		err := ctx.Bind(&request)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "err": sdkErrors.ErrInvalidObjectStructure.Error()})
+			return
+		}
+
+		if request.Name == "" || request.Scope == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Name and/or scope is blank. Both fields must have content"})
+			return
+		}
+
+		if request.ExpiresInDays < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "expiresInDays must not be negative. Use 0 for a key that does not expire"})
+			return
+		}
+
+		ttl := time.Duration(request.ExpiresInDays) * 24 * time.Hour
+		rawKey, key, err := auth.NewAPIKey(server, ctx.GetString("userEmail"), request.Name, request.Scope, auth.CallerScope(ctx), ttl)
+		if errors.Is(err, auth.ErrAPIKeyScopeNotHeld) {
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "API keys can only be granted scopes that you hold", "err": err.Error(), "callerScope": auth.CallerScope(ctx)})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create API key", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully created API key. Store this key now, as it cannot be shown again", "apiKey": rawKey, "key": key})
+	}
+}
+
+/*
+APIKeyDELETE Gin handler for the DELETE request to the API Key Endpoint. This function should not be called
+directly and should only be passed to the gin router
+*/
+func APIKeyDELETE(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		email := ctx.DefaultQuery("email", userEmail)
+
+		if !authorize(ctx, auth.ResourceUser, auth.ActionWrite, email) {
+			return
+		}
+
+		keyId := ctx.Query("keyId")
+		if keyId == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "A keyId is required to revoke an API key"})
+			return
+		}
+
+		err := auth.RevokeAPIKey(server, keyId, email)
+		if errors.Is(err, auth.ErrNoAPIKey) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find API key with the specified keyId", "err": err.Error(), "keyId": keyId})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to revoke API key", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully revoked API key", "keyId": keyId})
+	}
+}
@@ -8,57 +8,48 @@ import (
 	"github.com/stevezaluk/mtgjson-sdk/card"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"mtgjson/auth"
+	"mtgjson/index"
 	"net/http"
-	"strconv"
 )
 
 /*
-limitToInt64 Convert the limit argument from a string to a 64-bit integer
-*/
-func limitToInt64(limit string) int64 {
-	ret, err := strconv.ParseInt(limit, 10, 64)
-	if err != nil {
-		return int64(100)
-	}
-
-	return ret
-}
-
-/*
-CardGET Gin handler for GET request to the Card endpoint. This should not be called directly and
-should only be passed to the gin router
+CardGET Gin handler for GET request to the Card endpoint. A card can be fetched either by its cardId
+(mtgjsonV4Id), or by any other identifier using the 'idType' and 'id' query parameters. Lookups by idType
+are made against the catalog, as they are used to match cards known to other services. This should not be
+called directly and should only be passed to the gin router
 */
 func CardGET(server *server.Server) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
+		if ctx.Query("idType") != "" {
+			owner = catalogOwner(ctx)
+		}
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return
+		}
 
-		if owner != "system" && owner != userEmail {
-			if !auth.ValidateScope(ctx, "read:card.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to read other users cards", "requiredScope": "read:user-card"})
+		if ctx.Query("cardId") == "" && ctx.Query("idType") == "" {
+			page, err := pageFromQuery(ctx)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "The cursor passed in the query is not valid", "err": err.Error()})
 				return
 			}
-		}
 
-		cardId := ctx.Query("cardId")
-		if cardId == "" {
-			limit := limitToInt64(ctx.DefaultQuery("limit", "100"))
-			results, err := card.IndexCards(server.Database(), limit) // update this function with owner
-			if errors.Is(err, sdkErrors.ErrNoCards) {
-				ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to find cards in the database to index", "err": err.Error()})
+			results, total, err := index.Cards(server, owner, page)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to index cards", "err": err.Error()})
 				return
 			}
 
+			writePageHeaders(ctx, page, total)
 			ctx.JSON(http.StatusOK, results)
 			return
 		}
 
-		results, err := card.GetCard(server.Database(), cardId, owner)
-		if errors.Is(err, sdkErrors.ErrNoCard) {
-			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find card with specified cardId", "err": err.Error(), "cardId": cardId})
-			return
-		} else if errors.Is(err, sdkErrors.ErrInvalidUUID) {
-			ctx.JSON(http.StatusBadRequest, gin.H{"message": "cardId is not a valid V5 UUID", "err": err.Error(), "cardId": cardId})
+		results, ok := fetchCard(ctx, server, owner)
+		if !ok {
 			return
 		}
 
@@ -67,27 +58,72 @@ func CardGET(server *server.Server) gin.HandlerFunc {
 }
 
 /*
-CardPOST Gin handler for POST request to the Card endpoint. This should not be called directly and
-should only be passed to the gin router
+fetchCard - Fetch the single card owned by owner that is requested either by the 'cardId' query parameter,
+or by the 'idType' and 'id' query parameters. If the card cannot be fetched, then an error response is
+written to the context and false is returned
 */
-func CardPOST(server *server.Server) gin.HandlerFunc {
+func fetchCard(ctx *gin.Context, server *server.Server, owner string) (*cardModel.CardSet, bool) {
+	if owner == auth.AllOwners {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "An owner of '*' can only be used when listing cards"})
+		return nil, false
+	}
+
+	idType := ctx.Query("idType")
+	if idType != "" {
+		id := ctx.Query("id")
+		if id == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "An id is required when fetching a card by idType", "idType": idType})
+			return nil, false
+		}
+
+		result, err := index.GetCardByIdentifier(server, owner, idType, id)
+		if errors.Is(err, index.ErrInvalidIdType) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Cards cannot be fetched by the requested idType", "err": err.Error(), "idType": idType, "idTypes": index.IdTypes()})
+			return nil, false
+		} else if errors.Is(err, sdkErrors.ErrNoCard) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find card with specified identifier", "err": err.Error(), "idType": idType, "id": id})
+			return nil, false
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch card by identifier", "err": err.Error()})
+			return nil, false
+		}
+
+		return result, true
+	}
+
+	cardId := ctx.Query("cardId")
+	if cardId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Either a cardId, or an idType and id, is required to fetch a card", "err": sdkErrors.ErrCardMissingId.Error()})
+		return nil, false
+	}
+
+	result, err := card.GetCard(server.Database(), cardId, owner)
+	if errors.Is(err, sdkErrors.ErrNoCard) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find card with specified cardId", "err": err.Error(), "cardId": cardId})
+		return nil, false
+	} else if errors.Is(err, sdkErrors.ErrInvalidUUID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "cardId is not a valid V5 UUID. Use the idType and id parameters to fetch a card by another identifier", "err": err.Error(), "cardId": cardId})
+		return nil, false
+	} else if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch card", "err": err.Error()})
+		return nil, false
+	}
+
+	return result, true
+}
+
+/*
+CardPOST Gin handler for POST request to the Card endpoint. The new card is added to the card name
+index once it is created. This should not be called directly and should only be passed to the gin router
+*/
+func CardPOST(server *server.Server, names *index.NameIndex) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:card.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify of system or pre-constructed cards", "requiredScope": "write:system-card"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:card.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify another users card's", "requiredScope": "write:user-card"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceCard, auth.ActionWrite, owner) {
+			return
 		}
 
 		var newCard *cardModel.CardSet
@@ -122,32 +158,24 @@ func CardPOST(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
+		names.Add(owner, newCard.Name, newCard.Identifiers.MtgjsonV4Id)
+
 		ctx.JSON(http.StatusOK, gin.H{"message": "New card created successfully", "cardId": newCard.Identifiers.MtgjsonV4Id})
 	}
 }
 
 /*
-CardDELETE Gin handler for DELETE request to the Card endpoint. This should not be called directly and
-should only be passed to the gin router
+CardDELETE Gin handler for DELETE request to the Card endpoint. The card is removed from the card name
+index once it is deleted. This should not be called directly and should only be passed to the gin router
 */
-func CardDELETE(server *server.Server) gin.HandlerFunc {
+func CardDELETE(server *server.Server, names *index.NameIndex) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:deck.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify of system or pre-constructed cards", "requiredScope": "write:system-card"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify another users cards", "requiredScope": "write:user-card"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceCard, auth.ActionWrite, owner) {
+			return
 		}
 
 		cardId := ctx.Query("cardId")
@@ -165,6 +193,8 @@ func CardDELETE(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
+		names.Remove(owner, cardId)
+
 		ctx.JSON(http.StatusOK, gin.H{"message": "Card successfully deleted", "cardId": cardId})
 	}
 }
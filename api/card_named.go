@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/card"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/index"
+	"net/http"
+	"strconv"
+)
+
+// defaultAutocompleteLimit - The amount of suggestions returned by the Card Autocomplete endpoint if a limit is not passed
+const defaultAutocompleteLimit = 20
+
+/*
+CardAutocompleteGET Gin handler for the GET request to the Card Autocomplete endpoint. Returns the names of
+cards that begin with the 'prefix' query parameter. This function should not be called directly and should
+only be passed to the gin router
+*/
+func CardAutocompleteGET(server *server.Server, names *index.NameIndex) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner := catalogOwner(ctx)
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return
+		}
+
+		prefix := ctx.Query("prefix")
+		if prefix == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "A prefix is required to autocomplete card names"})
+			return
+		}
+
+		limit, err := strconv.Atoi(ctx.Query("limit"))
+		if err != nil || limit <= 0 || limit > defaultAutocompleteLimit {
+			limit = defaultAutocompleteLimit
+		}
+
+		ctx.JSON(http.StatusOK, names.Autocomplete(owner, prefix, limit))
+	}
+}
+
+/*
+CardNamedGET Gin handler for the GET request to the Card Named endpoint. Resolves the possibly misspelled
+name in the 'fuzzy' query parameter to a single card. This function should not be called directly and should
+only be passed to the gin router
+*/
+func CardNamedGET(server *server.Server, names *index.NameIndex) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner := catalogOwner(ctx)
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return
+		}
+
+		fuzzy := ctx.Query("fuzzy")
+		if fuzzy == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "A fuzzy name is required to look up a card"})
+			return
+		}
+
+		match, err := names.Fuzzy(owner, fuzzy)
+		if errors.Is(err, index.ErrNoCardName) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find a card with a name close to the one requested", "err": err.Error(), "fuzzy": fuzzy})
+			return
+		} else if errors.Is(err, index.ErrAmbiguousCardName) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "The requested name matches more than one card. Try a more specific name", "err": err.Error(), "fuzzy": fuzzy})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to look up card name", "err": err.Error()})
+			return
+		}
+
+		result, err := card.GetCard(server.Database(), match.CardId, match.Owner)
+		if errors.Is(err, sdkErrors.ErrNoCard) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find card with the matched name", "err": err.Error(), "cardId": match.CardId})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch card with the matched name", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/price"
+	"net/http"
+	"time"
+)
+
+/*
+priceFilterFromQuery - Build a price filter from the query parameters of the request. The card is either
+passed directly in the 'uuid' query parameter, or requested in the same way as CardGET. If the card cannot be
+fetched or a date cannot be parsed, then an error response is written to the context and false is returned
+*/
+func priceFilterFromQuery(ctx *gin.Context, server *server.Server) (price.Filter, bool) {
+	filter := price.Filter{
+		Uuid:             ctx.Query("uuid"),
+		GameAvailability: ctx.Query("gameAvailability"),
+		Provider:         ctx.Query("provider"),
+		PriceType:        ctx.Query("priceType"),
+		Finish:           ctx.Query("finish"),
+	}
+
+	if filter.Uuid == "" {
+		owner := catalogOwner(ctx)
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return filter, false
+		}
+
+		result, ok := fetchCard(ctx, server, owner)
+		if !ok {
+			return filter, false
+		}
+
+		filter.Uuid = result.Uuid
+		if filter.Uuid == "" && result.Identifiers != nil {
+			filter.Uuid = result.Identifiers.MtgjsonV4Id
+		}
+	}
+
+	for key, dest := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := ctx.Query(key)
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(price.DateLayout, value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": key + " must be a date in the format YYYY-MM-DD", "err": err.Error()})
+			return filter, false
+		}
+
+		*dest = date
+	}
+
+	return filter, true
+}
+
+/*
+CardPriceGET Gin handler for the GET request to the Card Price endpoint. Returns the most recent price of the
+card from each provider, for each finish. This function should not be called directly and should only be
+passed to the gin router
+*/
+func CardPriceGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter, ok := priceFilterFromQuery(ctx, server)
+		if !ok {
+			return
+		}
+
+		results, err := price.Current(server, filter)
+		if errors.Is(err, price.ErrNoPrices) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find price data for the card", "err": err.Error(), "uuid": filter.Uuid})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch prices", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"uuid": filter.Uuid, "prices": results})
+	}
+}
+
+/*
+CardPriceHistoryGET Gin handler for the GET request to the Card Price History endpoint. Returns each price
+point recorded for the card, optionally between the 'from' and 'to' dates. This function should not be
+called directly and should only be passed to the gin router
+*/
+func CardPriceHistoryGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter, ok := priceFilterFromQuery(ctx, server)
+		if !ok {
+			return
+		}
+
+		results, err := price.History(server, filter)
+		if errors.Is(err, price.ErrNoPrices) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find price history for the card", "err": err.Error(), "uuid": filter.Uuid})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch price history", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"uuid": filter.Uuid, "history": results})
+	}
+}
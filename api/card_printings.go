@@ -0,0 +1,37 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/index"
+	"net/http"
+)
+
+/*
+CardPrintingsGET Gin handler for the GET request to the Card Printings endpoint. Lists every printing of the
+requested card across all sets, sorted by release date. The card is requested in the same way as CardGET. This
+function should not be called directly and should only be passed to the gin router
+*/
+func CardPrintingsGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner := catalogOwner(ctx)
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return
+		}
+
+		result, ok := fetchCard(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		printings, err := index.Printings(server, owner, result)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch printings of card", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, printings)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/index"
+	"net/http"
+	"strconv"
+)
+
+// maxResolveIdentifiers - The maximum amount of identifiers that can be resolved in a single request
+const maxResolveIdentifiers = 1000
+
+/*
+CardResolvePOST Gin handler for the POST request to the Card Resolve endpoint. Maps a list of identifiers of
+mixed types to the mtgjsonV4Id of the card each belongs to. This function should not be called directly and
+should only be passed to the gin router
+*/
+func CardResolvePOST(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner := catalogOwner(ctx)
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return
+		}
+
+		var request struct {
+			Identifiers []index.Identifier `json:"identifiers"`
+		}
+
+		err := ctx.BindJSON(&request)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "err": sdkErrors.ErrInvalidObjectStructure.Error()})
+			return
+		}
+
+		if len(request.Identifiers) == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "At least one identifier is required"})
+			return
+		}
+
+		if len(request.Identifiers) > maxResolveIdentifiers {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "No more than " + strconv.Itoa(maxResolveIdentifiers) + " identifiers can be resolved in a single request"})
+			return
+		}
+
+		results, err := index.ResolveIdentifiers(server, owner, request.Identifiers)
+		if errors.Is(err, index.ErrInvalidIdType) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "One or more identifiers has an unsupported idType", "err": err.Error(), "idTypes": index.IdTypes()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to resolve identifiers", "err": err.Error()})
+			return
+		}
+
+		var unresolved int
+		for _, result := range results {
+			if !result.Found {
+				unresolved++
+			}
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"resolved": len(results) - unresolved, "unresolved": unresolved, "results": results})
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/index"
+	"mtgjson/query"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/*
+splitList - Split a comma separated query parameter into its values, dropping empty values
+*/
+func splitList(value string, upper bool) []string {
+	var ret []string
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if upper {
+			item = strings.ToUpper(item)
+		}
+
+		ret = append(ret, item)
+	}
+
+	return ret
+}
+
+/*
+parseFloatQuery - Parse an optional floating point query parameter. Returns nil if the parameter is not set
+*/
+func parseFloatQuery(ctx *gin.Context, key string) (*float64, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	ret, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+/*
+cardSearchFromQuery - Build a card search from the query parameters of the request
+*/
+func cardSearchFromQuery(ctx *gin.Context) (index.CardSearch, error) {
+	search := index.CardSearch{
+		Name:          ctx.Query("name"),
+		Text:          ctx.Query("text"),
+		Type:          ctx.Query("type"),
+		Colors:        splitList(ctx.Query("colors"), true),
+		ColorIdentity: splitList(ctx.Query("colorIdentity"), true),
+		Power:         ctx.Query("power"),
+		Toughness:     ctx.Query("toughness"),
+		Rarity:        ctx.Query("rarity"),
+		SetCode:       ctx.Query("setCode"),
+		Keywords:      splitList(ctx.Query("keywords"), false),
+		Sort:          ctx.Query("sort"),
+	}
+
+	var err error
+	for key, dest := range map[string]**float64{"manaValue": &search.ManaValue, "manaValueMin": &search.ManaValueMin, "manaValueMax": &search.ManaValueMax} {
+		*dest, err = parseFloatQuery(ctx, key)
+		if err != nil {
+			return search, errors.New(key + " must be a number")
+		}
+	}
+
+	return search, nil
+}
+
+/*
+CardSearchGET Gin handler for the GET request to the Card Search endpoint. This function should not be called
+directly and should only be passed to the gin router
+*/
+func CardSearchGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner := catalogOwner(ctx)
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, owner) {
+			return
+		}
+
+		search, err := cardSearchFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to parse search parameters", "err": err.Error()})
+			return
+		}
+
+		if q := ctx.Query("q"); q != "" {
+			search.Query, err = query.Filter(q)
+
+			var syntaxErr *query.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"message":  "Failed to parse search query",
+					"err":      syntaxErr.Error(),
+					"position": syntaxErr.Position,
+					"token":    syntaxErr.Token,
+					"reason":   syntaxErr.Message,
+				})
+				return
+			} else if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to parse search query", "err": err.Error()})
+				return
+			}
+		}
+
+		page, err := pageFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The cursor passed in the query is not valid", "err": err.Error()})
+			return
+		}
+
+		results, total, err := index.SearchCards(server, owner, search, page)
+		if errors.Is(err, index.ErrInvalidSort) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Cards cannot be sorted on the requested field", "err": err.Error()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to search cards", "err": err.Error()})
+			return
+		}
+
+		writePageHeaders(ctx, page, total)
+		ctx.JSON(http.StatusOK, results)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	deckModel "github.com/stevezaluk/mtgjson-models/deck"
 	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
@@ -9,6 +10,8 @@ import (
 	"github.com/stevezaluk/mtgjson-sdk/deck"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"mtgjson/auth"
+	"mtgjson/deckmeta"
+	"mtgjson/index"
 	"net/http"
 )
 
@@ -22,26 +25,34 @@ func DeckGET(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner != "system" && owner != userEmail { // caller is trying to read another users deck
-			if !auth.ValidateScope(ctx, "read:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to read other users decks", "requiredScope": "read:user-deck"})
-				return
-			}
+		code := ctx.Query("deckCode")
+		if !authorizeDeckRead(ctx, server, code, owner) {
+			return
 		}
 
-		code := ctx.Query("deckCode")
 		if code == "" {
-			limit := limitToInt64(ctx.DefaultQuery("limit", "100"))
-			results, err := deck.IndexDecks(server.Database(), limit) // update this function with owner
-			if errors.Is(err, sdkErrors.ErrNoDecks) {
-				ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to find decks in the database to index", "err": err.Error()})
+			page, err := pageFromQuery(ctx)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "The cursor passed in the query is not valid", "err": err.Error()})
 				return
 			}
 
+			results, total, err := index.Decks(server, owner, page)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to index decks", "err": err.Error()})
+				return
+			}
+
+			writePageHeaders(ctx, page, total)
 			ctx.JSON(http.StatusOK, results)
 			return
 		}
 
+		if owner == auth.AllOwners {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "An owner of '*' can only be used when listing decks"})
+			return
+		}
+
 		results, err := deck.GetDeck(server.Database(), code, owner)
 		if errors.Is(err, sdkErrors.ErrNoDeck) {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find deck under the specified deck code", "err": err.Error(), "deckCode": code})
@@ -62,18 +73,8 @@ func DeckPOST(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:deck.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify of system or pre-constructed decks", "requiredScope": "write:system-deck"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify another users deck content", "requiredScope": "write:user-deck"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceDeck, auth.ActionWrite, owner) {
+			return
 		}
 
 		var newDeck *deckModel.Deck
@@ -101,15 +102,26 @@ func DeckPOST(server *server.Server) gin.HandlerFunc {
 			}
 		}
 
-		// handle deck contents here
+		format := ctx.Query("format")
+		if !enforceFormat(ctx, server, format, newDeck.Contents) {
+			return
+		}
 
-		var err = deck.NewDeck(server.Database(), newDeck, owner)
+		var err = newDeckWithMeta(server, newDeck, owner, func() error {
+			if format == "" {
+				return nil
+			}
+			return deckmeta.SetFormat(server, newDeck.Code, owner, format)
+		})
 		if errors.Is(err, sdkErrors.ErrDeckMissingId) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck is missing a name and/or a deck code. Both of these values must be filled", "err": err.Error()})
 			return
 		} else if errors.Is(err, sdkErrors.ErrDeckAlreadyExists) {
 			ctx.JSON(http.StatusConflict, gin.H{"message": "Deck already exists under this deck code", "err": err.Error(), "deckCode": newDeck.Code})
 			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create deck", "err": err.Error()})
+			return
 		}
 
 		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully created new deck", "deckCode": newDeck.Code})
@@ -125,18 +137,9 @@ func DeckDELETE(server *server.Server) gin.HandlerFunc {
 
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
-		if owner == "system" { // caller is trying to delete a system created (pre-constructed) deck
-			if !auth.ValidateScope(ctx, "write:deck.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to delete pre-constructed decks", "requiredScope": "write:system-deck"})
-				return
-			}
-		}
 
-		if owner != userEmail { // caller is trying to delete a different users deck
-			if !auth.ValidateScope(ctx, "write:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to delete other users decks", "requiredScope": "write:user-deck"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceDeck, auth.ActionWrite, owner) {
+			return
 		}
 
 		code := ctx.Query("deckCode")
@@ -160,3 +163,29 @@ func DeckDELETE(server *server.Server) gin.HandlerFunc {
 		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully deleted deck", "deckCode": _deck.Code})
 	}
 }
+
+/*
+newDeckWithMeta - Create a new deck owned by owner, and then record metadata on it by calling each of the
+passed functions in order. The SDK cannot store this metadata while creating the deck, so if any of these
+functions fail, then the deck is deleted so that it is not left behind without its metadata
+*/
+func newDeckWithMeta(server *server.Server, newDeck *deckModel.Deck, owner string, meta ...func() error) error {
+	err := deck.NewDeck(server.Database(), newDeck, owner)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range meta {
+		err = record()
+		if err != nil {
+			deleteErr := deck.DeleteDeck(server.Database(), newDeck.Code, owner)
+			if deleteErr != nil {
+				return fmt.Errorf("%w (the deck could not be removed: %v)", err, deleteErr)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/deck"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/deckmeta"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxCloneCodeAttempts - The amount of suffixed deck codes tried when generating a code for a clone
+const maxCloneCodeAttempts = 100
+
+/*
+cloneDeckCode - Generate a deck code for a clone of the deck with the passed code that is not already used
+by owner. The code of the source deck is suffixed with -COPY, followed by a number if that is taken as well
+(ex. M21-COPY-2)
+*/
+func cloneDeckCode(server *server.Server, code string, owner string) (string, error) {
+	for attempt := 1; attempt <= maxCloneCodeAttempts; attempt++ {
+		candidate := code + "-COPY"
+		if attempt > 1 {
+			candidate += "-" + strconv.Itoa(attempt)
+		}
+
+		_, err := deck.GetDeck(server.Database(), candidate, owner)
+		if errors.Is(err, sdkErrors.ErrNoDeck) {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("api: no unused deck code found for a clone of %s after %d attempts", code, maxCloneCodeAttempts)
+}
+
+/*
+DeckClonePOST Gin handler for the POST request to the Deck Clone endpoint. Copies a deck, including its
+contents, into the caller's account and records the deck that it was copied from. Public decks, and unlisted
+decks along with their share token, can be cloned by any caller. This function should not be called directly
+and should only be passed to the gin router
+*/
+func DeckClonePOST(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		code := ctx.Query("deckCode")
+		if !authorizeDeckRead(ctx, server, code, owner) {
+			return
+		}
+
+		if !authorize(ctx, auth.ResourceDeck, auth.ActionWrite, userEmail) {
+			return
+		}
+
+		if owner == auth.AllOwners {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "An owner of '*' can only be used when listing decks"})
+			return
+		}
+
+		if code == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck code is required to clone a deck", "err": sdkErrors.ErrDeckMissingId.Error()})
+			return
+		}
+
+		source, err := deck.GetDeck(server.Database(), code, owner)
+		if errors.Is(err, sdkErrors.ErrNoDeck) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find deck under the specified deck code", "err": err.Error(), "deckCode": code})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch deck", "err": err.Error()})
+			return
+		}
+
+		format, err := deckmeta.GetFormat(server, code, owner)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch the format of the deck", "err": err.Error()})
+			return
+		}
+
+		newCode := ctx.Query("newDeckCode")
+		if newCode == "" {
+			newCode, err = cloneDeckCode(server, source.Code, userEmail)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to generate a deck code for the clone", "err": err.Error()})
+				return
+			}
+		}
+
+		clone := &deckModel.Deck{
+			Code:        newCode,
+			Name:        ctx.DefaultQuery("name", source.Name),
+			Type:        source.Type,
+			ReleaseDate: source.ReleaseDate,
+			Contents:    mergeContentIds(source.Contents, nil),
+		}
+
+		provenance := &deckmeta.Provenance{DeckCode: source.Code, Owner: owner, Name: source.Name, ClonedAt: time.Now().UTC()}
+
+		err = newDeckWithMeta(server, clone, userEmail,
+			func() error {
+				return deckmeta.SetProvenance(server, clone.Code, userEmail, provenance)
+			},
+			func() error {
+				if format == "" {
+					return nil
+				}
+				return deckmeta.SetFormat(server, clone.Code, userEmail, format)
+			},
+		)
+		if errors.Is(err, sdkErrors.ErrDeckMissingId) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck is missing a name and/or a deck code. Both of these values must be filled", "err": err.Error()})
+			return
+		} else if errors.Is(err, sdkErrors.ErrDeckAlreadyExists) {
+			ctx.JSON(http.StatusConflict, gin.H{"message": "Deck already exists under this deck code. Pass a different newDeckCode, or omit it to generate one", "err": err.Error(), "deckCode": clone.Code})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to clone deck", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully cloned deck", "deckCode": clone.Code, "clonedFrom": provenance})
+	}
+}
+
+/*
+DeckProvenanceGET Gin handler for the GET request to the Deck Provenance endpoint. Returns the deck that a deck
+was cloned from, if any. This function should not be called directly and should only be passed to the gin
+router
+*/
+func DeckProvenanceGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		code := ctx.Query("deckCode")
+		if !authorizeDeckRead(ctx, server, code, owner) {
+			return
+		}
+
+		if code == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck code is required to fetch a deck's provenance", "err": sdkErrors.ErrDeckMissingId.Error()})
+			return
+		}
+
+		provenance, err := deckmeta.GetProvenance(server, code, owner)
+		if errors.Is(err, sdkErrors.ErrNoDeck) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find deck under the specified deck code", "err": err.Error(), "deckCode": code})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch deck provenance", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": code, "clonedFrom": provenance})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stevezaluk/mtgjson-sdk/deck"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"mtgjson/auth"
+	"mtgjson/deckmeta"
 	"net/http"
 )
 
@@ -21,14 +22,12 @@ func DeckContentGET(server *server.Server) gin.HandlerFunc {
 
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
-		if owner != "system" && owner != userEmail { // caller is trying to read the contents of another users deck
-			if !auth.ValidateScope(ctx, "read:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to read other users decks", "requiredScope": "read:user-deck"})
-				return
-			}
-		}
 
 		code := ctx.Query("deckCode")
+		if !authorizeDeckRead(ctx, server, code, owner) {
+			return
+		}
+
 		if code == "" {
 			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck code is required to fetch a deck's contents", "err": sdkErrors.ErrDeckMissingId.Error()})
 			return
@@ -43,6 +42,7 @@ func DeckContentGET(server *server.Server) gin.HandlerFunc {
 		contents, err := deck.GetDeckContents(server.Database(), _deck)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "Error fetching deck contents", "err": err.Error()})
+			return
 		}
 
 		ctx.JSON(http.StatusOK, contents)
@@ -59,18 +59,8 @@ func DeckContentPOST(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:deck.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify content of system or pre-constructed decks", "requiredScope": "write:system-deck"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify another users deck content", "requiredScope": "write:user-deck"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceDeck, auth.ActionWrite, owner) {
+			return
 		}
 
 		code := ctx.Query("deckCode")
@@ -93,7 +83,7 @@ func DeckContentPOST(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
-		allCardIds := deck.AllCardIds(requestedDeck.Contents)
+		allCardIds := deck.AllCardIds(&request)
 		err, invalidCards, noExistCards := card.ValidateCards(server.Database(), allCardIds)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update deck. Error while validating cards", "err": err.Error()})
@@ -105,6 +95,16 @@ func DeckContentPOST(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
+		format, err := deckmeta.GetFormat(server, code, owner)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch the format of the deck", "err": err.Error()})
+			return
+		}
+
+		if !enforceFormat(ctx, server, format, mergeContentIds(requestedDeck.Contents, &request)) {
+			return
+		}
+
 		err = deck.AddCards(server.Database(), requestedDeck, &request)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to add cards to deck", "err": err.Error()})
@@ -124,18 +124,8 @@ func DeckContentDELETE(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:deck.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify content of system or pre-constructed decks", "requiredScope": "write:system-deck"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:deck.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify another users deck content", "requiredScope": "write:user-deck"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceDeck, auth.ActionWrite, owner) {
+			return
 		}
 
 		code := ctx.Query("deckCode")
@@ -158,7 +148,7 @@ func DeckContentDELETE(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
-		allCardIds := deck.AllCardIds(requestedDeck.Contents)
+		allCardIds := deck.AllCardIds(&request)
 		err, invalidCards, noExistCards := card.ValidateCards(server.Database(), allCardIds)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update deck. Error while validating cards", "err": err.Error()})
@@ -170,6 +160,16 @@ func DeckContentDELETE(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
+		format, err := deckmeta.GetFormat(server, code, owner)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch the format of the deck", "err": err.Error()})
+			return
+		}
+
+		if !enforceFormat(ctx, server, format, subtractContentIds(requestedDeck.Contents, &request)) {
+			return
+		}
+
 		err = deck.RemoveCards(server.Database(), requestedDeck, &request)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to remove cards from deck", "err": err.Error()})
@@ -179,3 +179,45 @@ func DeckContentDELETE(server *server.Server) gin.HandlerFunc {
 		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully removed cards from deck", "deckCode": code}) // re-add count here
 	}
 }
+
+/*
+fetchDeckContents - Fetch the deck owned by owner with the code in the 'deckCode' query parameter, along with
+its contents. If either cannot be fetched, then an error response is written to the context and false is
+returned
+*/
+func fetchDeckContents(ctx *gin.Context, server *server.Server, owner string) (*deckModel.Deck, *deckModel.DeckContents, bool) {
+	return loadDeckContents(ctx, server, ctx.Query("deckCode"), owner)
+}
+
+/*
+loadDeckContents - Fetch the deck owned by owner with the passed code, along with its contents. If either
+cannot be fetched, then an error response is written to the context and false is returned
+*/
+func loadDeckContents(ctx *gin.Context, server *server.Server, code string, owner string) (*deckModel.Deck, *deckModel.DeckContents, bool) {
+	if owner == auth.AllOwners {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "An owner of '*' can only be used when listing decks"})
+		return nil, nil, false
+	}
+
+	if code == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck code is required to fetch a deck's contents", "err": sdkErrors.ErrDeckMissingId.Error()})
+		return nil, nil, false
+	}
+
+	requestedDeck, err := deck.GetDeck(server.Database(), code, owner)
+	if errors.Is(err, sdkErrors.ErrNoDeck) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find deck with the specified deck code", "err": err.Error(), "deckCode": code})
+		return nil, nil, false
+	} else if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch deck", "err": err.Error()})
+		return nil, nil, false
+	}
+
+	contents, err := deck.GetDeckContents(server.Database(), requestedDeck)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Error fetching deck contents", "err": err.Error()})
+		return nil, nil, false
+	}
+
+	return requestedDeck, contents, true
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/decklist"
+	"net/http"
+)
+
+/*
+DeckDiffGET Gin handler for the GET request to the Deck Diff endpoint. Compares two decks, which may belong to
+different owners, and returns the cards added, removed and changed in each board. Either deck may be shared,
+in which case its share token is passed in 'fromShareToken' or 'toShareToken'. This function should not
+be called directly and should only be passed to the gin router
+*/
+func DeckDiffGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+		fromOwner := ctx.DefaultQuery("fromOwner", owner)
+		toOwner := ctx.DefaultQuery("toOwner", owner)
+
+		from, to := ctx.Query("from"), ctx.Query("to")
+		shareToken := ctx.Query("shareToken")
+
+		if !authorizeSharedDeckRead(ctx, server, from, fromOwner, ctx.DefaultQuery("fromShareToken", shareToken)) {
+			return
+		}
+
+		if !authorizeSharedDeckRead(ctx, server, to, toOwner, ctx.DefaultQuery("toShareToken", shareToken)) {
+			return
+		}
+
+		fromDeck, fromContents, ok := loadDeckContents(ctx, server, from, fromOwner)
+		if !ok {
+			return
+		}
+
+		toDeck, toContents, ok := loadDeckContents(ctx, server, to, toOwner)
+		if !ok {
+			return
+		}
+
+		diff := decklist.Compare(fromContents, toContents)
+
+		switch ctx.DefaultQuery("format", "json") {
+		case "json":
+			ctx.JSON(http.StatusOK, gin.H{"from": fromDeck.Code, "to": toDeck.Code, "diff": diff})
+		case "text":
+			ctx.String(http.StatusOK, diff.Text())
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The requested format is not supported. Must be one of json or text", "err": decklist.ErrInvalidFormat.Error()})
+		}
+	}
+}
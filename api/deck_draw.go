@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"math/rand/v2"
+	"mtgjson/analytics"
+	"net/http"
+	"strconv"
+)
+
+/*
+parseIntQuery Parse an optional integer query parameter. Returns def if the parameter is not set
+*/
+func parseIntQuery(ctx *gin.Context, key string, def int) (int, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+/*
+targetFromQuery Build the target cards of a probability from the query parameters of the request. Cards are
+passed by repeating the 'card' parameter, as card names may contain commas
+*/
+func targetFromQuery(ctx *gin.Context) analytics.Target {
+	return analytics.Target{Cards: ctx.QueryArray("card"), Category: ctx.Query("category")}
+}
+
+/*
+DeckDrawGET Gin handler for the GET request to the Deck Draw endpoint. Computes the probability of drawing the
+requested cards by each turn. This function should not be called directly and should only be passed to the
+gin router
+*/
+func DeckDrawGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		if !authorizeDeckRead(ctx, server, ctx.Query("deckCode"), owner) {
+			return
+		}
+
+		copies, err := parseIntQuery(ctx, "copies", 1)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The copies query parameter must be a number", "err": err.Error()})
+			return
+		}
+
+		turns, err := parseIntQuery(ctx, "turns", 1)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The turns query parameter must be a number", "err": err.Error()})
+			return
+		}
+
+		requestedDeck, contents, ok := fetchDeckContents(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		result, err := analytics.DrawProbability(contents, analytics.DrawOptions{
+			Target:    targetFromQuery(ctx),
+			Copies:    int64(copies),
+			Turns:     turns,
+			OnTheDraw: ctx.Query("onThe") == "draw",
+		})
+		if errors.Is(err, analytics.ErrNoTarget) || errors.Is(err, analytics.ErrEmptyLibrary) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to compute draw probability", "err": err.Error()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to compute draw probability", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": requestedDeck.Code, "name": requestedDeck.Name, "result": result})
+	}
+}
+
+/*
+DeckSimulateGET Gin handler for the GET request to the Deck Simulate endpoint. Simulates opening hands using
+the London mulligan. If a seed is not passed, then one is generated and returned so that the simulation can
+be reproduced. This function should not be called directly and should only be passed to the gin router
+*/
+func DeckSimulateGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		if !authorizeDeckRead(ctx, server, ctx.Query("deckCode"), owner) {
+			return
+		}
+
+		opts := analytics.SimulationOptions{
+			Target:        targetFromQuery(ctx),
+			RequireTarget: ctx.Query("requireTarget") == "true",
+			Seed:          rand.Uint64(),
+		}
+
+		if value := ctx.Query("seed"); value != "" {
+			seed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "The seed query parameter must be a positive number", "err": err.Error()})
+				return
+			}
+			opts.Seed = seed
+		}
+
+		params := []struct {
+			key   string
+			value *int
+		}{
+			{"trials", &opts.Trials},
+			{"minLands", &opts.MinLands},
+			{"maxLands", &opts.MaxLands},
+			{"minHandSize", &opts.MinHandSize},
+		}
+
+		for _, param := range params {
+			value, err := parseIntQuery(ctx, param.key, 0)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "The " + param.key + " query parameter must be a number", "err": err.Error()})
+				return
+			}
+			*param.value = value
+		}
+
+		if opts.RequireTarget && opts.Target.IsEmpty() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "A card or category is required when requireTarget is set", "err": analytics.ErrNoTarget.Error()})
+			return
+		}
+
+		requestedDeck, contents, ok := fetchDeckContents(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		result, err := analytics.Simulate(contents, opts)
+		if errors.Is(err, analytics.ErrEmptyLibrary) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to simulate opening hands", "err": err.Error()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to simulate opening hands", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": requestedDeck.Code, "name": requestedDeck.Name, "result": result})
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/decklist"
+	"net/http"
+)
+
+/*
+DeckExportGET Gin handler for the GET request to the Deck Export endpoint. Renders a deck into an Arena, MTGO,
+plain text, CSV or JSON decklist and serves it as a download. This function should not be called directly
+and should only be passed to the gin router
+*/
+func DeckExportGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		if !authorizeDeckRead(ctx, server, ctx.Query("deckCode"), owner) {
+			return
+		}
+
+		requestedDeck, contents, ok := fetchDeckContents(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		export, err := decklist.Render(requestedDeck, contents, ctx.DefaultQuery("format", decklist.FormatArena))
+		if errors.Is(err, decklist.ErrInvalidFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The requested format is not supported. Must be one of arena, mtgo, text, csv or json", "err": err.Error()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to export deck", "err": err.Error()})
+			return
+		}
+
+		ctx.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+		ctx.Data(http.StatusOK, export.ContentType, export.Body)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/deck"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/decklist"
+	"mtgjson/index"
+	"net/http"
+)
+
+/*
+DeckImportPOST Gin handler for the POST request to the Deck Import endpoint. Creates a new deck from an Arena,
+MTGO or plain text decklist passed as the request body. Cards are resolved against the cards owned by the
+cardOwner query parameter, and any lines that could not be imported are returned in the response. This
+function should not be called directly and should only be passed to the gin router
+*/
+func DeckImportPOST(server *server.Server, names *index.NameIndex) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+		cardOwner := ctx.DefaultQuery("cardOwner", auth.SystemOwner)
+
+		if !authorize(ctx, auth.ResourceDeck, auth.ActionWrite, owner) {
+			return
+		}
+
+		if !authorize(ctx, auth.ResourceCard, auth.ActionRead, cardOwner) {
+			return
+		}
+
+		code := ctx.Query("deckCode")
+		if code == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck code is required to import a deck", "err": sdkErrors.ErrDeckMissingId.Error()})
+			return
+		}
+
+		body, err := ctx.GetRawData()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read the decklist from the request body", "err": err.Error()})
+			return
+		}
+
+		list, err := decklist.Parse(string(body), ctx.Query("format"))
+		if errors.Is(err, decklist.ErrEmptyDecklist) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The decklist does not contain any cards", "err": err.Error(), "unresolved": list.Errors})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to parse the decklist", "err": err.Error()})
+			return
+		}
+
+		contents, unresolved, err := decklist.Resolve(server, names, cardOwner, list)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to resolve the cards in the decklist", "err": err.Error()})
+			return
+		}
+
+		imported := len(contents.MainBoard) + len(contents.SideBoard) + len(contents.Commander)
+		if imported == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "None of the cards in the decklist could be resolved", "err": sdkErrors.ErrInvalidCards.Error(), "unresolved": unresolved})
+			return
+		}
+
+		if ctx.Query("strict") == "true" && len(unresolved) != 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Some lines of the decklist could not be imported", "err": sdkErrors.ErrInvalidCards.Error(), "unresolved": unresolved})
+			return
+		}
+
+		name := ctx.Query("name")
+		if name == "" {
+			name = list.Name
+		}
+
+		if name == "" {
+			name = code
+		}
+
+		newDeck := &deckModel.Deck{
+			Code:     code,
+			Name:     name,
+			Type:     ctx.Query("type"),
+			Contents: contents,
+		}
+
+		err = deck.NewDeck(server.Database(), newDeck, owner)
+		if errors.Is(err, sdkErrors.ErrDeckMissingId) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck is missing a name and/or a deck code. Both of these values must be filled", "err": err.Error()})
+			return
+		} else if errors.Is(err, sdkErrors.ErrDeckAlreadyExists) {
+			ctx.JSON(http.StatusConflict, gin.H{"message": "Deck already exists under this deck code", "err": err.Error(), "deckCode": code})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create deck", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully imported deck", "deckCode": code, "format": list.Format, "imported": imported, "unresolved": unresolved})
+	}
+}
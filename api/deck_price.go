@@ -0,0 +1,42 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/price"
+	"net/http"
+)
+
+/*
+DeckPriceGET Gin handler for the GET request to the Deck Price endpoint. Values a deck using the most recent
+stored price of each card, broken down by board and by card. This function should not be called directly and
+should only be passed to the gin router
+*/
+func DeckPriceGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		if !authorizeDeckRead(ctx, server, ctx.Query("deckCode"), owner) {
+			return
+		}
+
+		requestedDeck, contents, ok := fetchDeckContents(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		valuation, err := price.ValueDeck(server, contents, price.ValuationOptions{
+			GameAvailability: ctx.Query("gameAvailability"),
+			Provider:         ctx.Query("provider"),
+			PriceType:        ctx.Query("priceType"),
+			Finish:           ctx.Query("finish"),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to value deck", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": requestedDeck.Code, "name": requestedDeck.Name, "valuation": valuation})
+	}
+}
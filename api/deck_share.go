@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
+	"mtgjson/deckmeta"
+	"net/http"
+)
+
+/*
+authorizeDeckRead - Evaluate whether the caller may read the deck owned by owner with the passed code. Callers
+that are allowed by ownership and scope policy may always read it. Otherwise public decks may be read by any
+caller, and unlisted decks by callers passing the deck's token in the 'shareToken' query parameter. If the
+caller is denied, then a 403 is written to the response and false is returned
+*/
+func authorizeDeckRead(ctx *gin.Context, server *server.Server, code string, owner string) bool {
+	return authorizeSharedDeckRead(ctx, server, code, owner, ctx.Query("shareToken"))
+}
+
+/*
+authorizeSharedDeckRead - Evaluate whether the caller may read the deck owned by owner with the passed code,
+using the passed share token for unlisted decks. This is used when a request reads more than one deck, and
+each deck may be shared with a different token
+*/
+func authorizeSharedDeckRead(ctx *gin.Context, server *server.Server, code string, owner string, shareToken string) bool {
+	decision := auth.Authorize(ctx, auth.ResourceDeck, auth.ActionRead, owner)
+	if decision.Allowed {
+		return true
+	}
+
+	if code != "" && owner != auth.AllOwners {
+		sharing, err := deckmeta.GetSharing(server, code, owner)
+		if err == nil && sharing.Allows(shareToken) {
+			return true
+		}
+	}
+
+	ctx.JSON(http.StatusForbidden, gin.H{"message": decision.Reason, "requiredScope": decision.RequiredScope})
+	return false
+}
+
+/*
+writeSharingError - Write the response for an error returned while reading or updating the sharing settings
+of a deck
+*/
+func writeSharingError(ctx *gin.Context, err error, code string) {
+	if errors.Is(err, sdkErrors.ErrNoDeck) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find deck under the specified deck code", "err": err.Error(), "deckCode": code})
+		return
+	} else if errors.Is(err, deckmeta.ErrInvalidVisibility) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "The requested visibility is not supported", "err": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update deck sharing", "err": err.Error()})
+}
+
+/*
+sharingRequest - Read the owner and deck code of a request to the Deck Share endpoints, and verify that the
+caller may modify the deck. If the request is invalid or the caller is denied, then an error response is
+written to the context and false is returned
+*/
+func sharingRequest(ctx *gin.Context, action auth.Action) (string, string, bool) {
+	userEmail := ctx.GetString("userEmail")
+	owner := ctx.DefaultQuery("owner", userEmail)
+
+	if !authorize(ctx, auth.ResourceDeck, action, owner) {
+		return "", "", false
+	}
+
+	if owner == auth.AllOwners {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "An owner of '*' can only be used when listing decks"})
+		return "", "", false
+	}
+
+	code := ctx.Query("deckCode")
+	if code == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Deck code is required to manage how a deck is shared", "err": sdkErrors.ErrDeckMissingId.Error()})
+		return "", "", false
+	}
+
+	return owner, code, true
+}
+
+/*
+DeckShareGET Gin handler for the GET request to the Deck Share endpoint. Returns the visibility of a deck and
+whether it has a share token. This function should not be called directly and should only be passed to the
+gin router
+*/
+func DeckShareGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner, code, ok := sharingRequest(ctx, auth.ActionRead)
+		if !ok {
+			return
+		}
+
+		sharing, err := deckmeta.GetSharing(server, code, owner)
+		if err != nil {
+			writeSharingError(ctx, err, code)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": code, "sharing": sharing})
+	}
+}
+
+/*
+DeckSharePOST Gin handler for the POST request to the Deck Share endpoint. Changes the visibility of a deck.
+If a deck is made unlisted without a share token, then one is created and returned. This function should
+not be called directly and should only be passed to the gin router
+*/
+func DeckSharePOST(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner, code, ok := sharingRequest(ctx, auth.ActionWrite)
+		if !ok {
+			return
+		}
+
+		err := deckmeta.SetVisibility(server, code, owner, ctx.Query("visibility"))
+		if err != nil {
+			writeSharingError(ctx, err, code)
+			return
+		}
+
+		sharing, err := deckmeta.GetSharing(server, code, owner)
+		if err != nil {
+			writeSharingError(ctx, err, code)
+			return
+		}
+
+		if sharing.Visibility == deckmeta.VisibilityUnlisted && !sharing.HasToken {
+			token, sharing, err := deckmeta.RotateShareToken(server, code, owner)
+			if err != nil {
+				writeSharingError(ctx, err, code)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, gin.H{"message": "Successfully updated deck visibility", "deckCode": code, "sharing": sharing, "shareToken": token})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully updated deck visibility", "deckCode": code, "sharing": sharing})
+	}
+}
+
+/*
+DeckShareTokenPOST Gin handler for the POST request to the Deck Share Token endpoint. Replaces the share token
+of a deck with a new one, so that links using the previous token stop working. This function should not be
+called directly and should only be passed to the gin router
+*/
+func DeckShareTokenPOST(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner, code, ok := sharingRequest(ctx, auth.ActionWrite)
+		if !ok {
+			return
+		}
+
+		token, sharing, err := deckmeta.RotateShareToken(server, code, owner)
+		if err != nil {
+			writeSharingError(ctx, err, code)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully rotated share token. This token will not be shown again", "deckCode": code, "sharing": sharing, "shareToken": token})
+	}
+}
+
+/*
+DeckShareTokenDELETE Gin handler for the DELETE request to the Deck Share Token endpoint. Revokes the share
+token of a deck, making it private if it was unlisted. This function should not be called directly and
+should only be passed to the gin router
+*/
+func DeckShareTokenDELETE(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner, code, ok := sharingRequest(ctx, auth.ActionWrite)
+		if !ok {
+			return
+		}
+
+		sharing, err := deckmeta.RevokeShareToken(server, code, owner)
+		if err != nil {
+			writeSharingError(ctx, err, code)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully revoked share token", "deckCode": code, "sharing": sharing})
+	}
+}
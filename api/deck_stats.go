@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/analytics"
+	"net/http"
+)
+
+/*
+DeckStatsGET Gin handler for the GET request to the Deck Stats endpoint. Computes the mana curve, color
+requirements and type breakdown of a deck. This function should not be called directly and should only be
+passed to the gin router
+*/
+func DeckStatsGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		if !authorizeDeckRead(ctx, server, ctx.Query("deckCode"), owner) {
+			return
+		}
+
+		requestedDeck, contents, ok := fetchDeckContents(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": requestedDeck.Code, "name": requestedDeck.Name, "stats": analytics.Compute(contents)})
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"github.com/stevezaluk/mtgjson-sdk/deck"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/deckmeta"
+	"mtgjson/legality"
+	"net/http"
+	"slices"
+)
+
+/*
+mergeContentIds Return the contents of a deck after the entries in added are added to it. Entries for the
+same card with the same finish are combined
+*/
+func mergeContentIds(existing *deckModel.DeckContentIds, added *deckModel.DeckContentIds) *deckModel.DeckContentIds {
+	merge := func(boards ...[]*deckModel.DeckContentEntry) []*deckModel.DeckContentEntry {
+		ret := []*deckModel.DeckContentEntry{}
+		byKey := make(map[string]*deckModel.DeckContentEntry)
+
+		for _, board := range boards {
+			for _, entry := range board {
+				key := entry.Uuid
+				if entry.IsFoil {
+					key += ":foil"
+				}
+
+				if found, ok := byKey[key]; ok {
+					found.Count += entry.Count
+					continue
+				}
+
+				merged := &deckModel.DeckContentEntry{Uuid: entry.Uuid, Count: entry.Count, IsFoil: entry.IsFoil}
+				byKey[key] = merged
+				ret = append(ret, merged)
+			}
+		}
+
+		return ret
+	}
+
+	if existing == nil {
+		existing = &deckModel.DeckContentIds{}
+	}
+
+	if added == nil {
+		added = &deckModel.DeckContentIds{}
+	}
+
+	return &deckModel.DeckContentIds{
+		MainBoard: merge(existing.MainBoard, added.MainBoard),
+		SideBoard: merge(existing.SideBoard, added.SideBoard),
+		Commander: merge(existing.Commander, added.Commander),
+	}
+}
+
+/*
+subtractContentIds Return the contents of a deck after the entries in removed are removed from it. Entries
+are matched by card and finish, and are dropped once none of their copies remain
+*/
+func subtractContentIds(existing *deckModel.DeckContentIds, removed *deckModel.DeckContentIds) *deckModel.DeckContentIds {
+	ret := mergeContentIds(existing, nil)
+	if removed == nil {
+		return ret
+	}
+
+	subtract := func(board []*deckModel.DeckContentEntry, removed []*deckModel.DeckContentEntry) []*deckModel.DeckContentEntry {
+		for _, entry := range removed {
+			for _, remaining := range board {
+				if remaining.Uuid == entry.Uuid && remaining.IsFoil == entry.IsFoil {
+					remaining.Count -= entry.Count
+				}
+			}
+		}
+
+		return slices.DeleteFunc(board, func(entry *deckModel.DeckContentEntry) bool {
+			return entry.Count <= 0
+		})
+	}
+
+	ret.MainBoard = subtract(ret.MainBoard, removed.MainBoard)
+	ret.SideBoard = subtract(ret.SideBoard, removed.SideBoard)
+	ret.Commander = subtract(ret.Commander, removed.Commander)
+
+	return ret
+}
+
+/*
+enforceFormat Validate deck contents against the declared format of a deck before they are written. If the
+deck does not have a format, then the contents are not validated. Violations that only mean the deck is not
+finished yet, such as having too few cards, are allowed so that a deck can be built up over several writes.
+If the contents break any other rule of the format, then the violations are written to the context and false
+is returned
+*/
+func enforceFormat(ctx *gin.Context, server *server.Server, format string, contents *deckModel.DeckContentIds) bool {
+	if format == "" {
+		return true
+	}
+
+	if contents == nil {
+		contents = &deckModel.DeckContentIds{}
+	}
+
+	cards, err := deck.GetDeckContents(server.Database(), &deckModel.Deck{Contents: contents})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Error fetching deck contents", "err": err.Error()})
+		return false
+	}
+
+	result, err := legality.Validate(cards, format)
+	if errors.Is(err, legality.ErrUnknownFormat) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "The requested format is not supported", "err": err.Error(), "formats": legality.Formats()})
+		return false
+	} else if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to validate deck", "err": err.Error()})
+		return false
+	}
+
+	if violations := result.Blocking(); len(violations) != 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "The deck is not legal in its format", "err": legality.ErrNotLegal.Error(), "format": result.Format, "violations": violations})
+		return false
+	}
+
+	return true
+}
+
+/*
+DeckValidateGET Gin handler for the GET request to the Deck Validate endpoint. Validates a deck against the
+construction rules of a format, and lists every rule that it breaks. If a format is not passed, then the
+deck is validated against the format it was created under. This function should not be called directly
+and should only be passed to the gin router
+*/
+func DeckValidateGET(server *server.Server) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userEmail := ctx.GetString("userEmail")
+		owner := ctx.DefaultQuery("owner", userEmail)
+
+		if !authorizeDeckRead(ctx, server, ctx.Query("deckCode"), owner) {
+			return
+		}
+
+		requestedDeck, contents, ok := fetchDeckContents(ctx, server, owner)
+		if !ok {
+			return
+		}
+
+		format := ctx.Query("format")
+		if format == "" {
+			declared, err := deckmeta.GetFormat(server, requestedDeck.Code, owner)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch the format of the deck", "err": err.Error()})
+				return
+			}
+			format = declared
+		}
+
+		if format == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "A format is required to validate a deck that was not created under one", "err": legality.ErrUnknownFormat.Error(), "formats": legality.Formats()})
+			return
+		}
+
+		result, err := legality.Validate(contents, format)
+		if errors.Is(err, legality.ErrUnknownFormat) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "The requested format is not supported", "err": err.Error(), "formats": legality.Formats()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to validate deck", "err": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"deckCode": requestedDeck.Code, "name": requestedDeck.Name, "result": result})
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"errors"
 	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/auth"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -14,7 +15,7 @@ import (
 LoginPOST Gin handler for the POST request to the Login Endpoint. This function should not be called
 directly and should only be passed to the gin router.
 */
-func LoginPOST(server *server.Server) gin.HandlerFunc {
+func LoginPOST(server *server.Server, provider auth.Provider) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		type LoginRequest struct {
@@ -42,8 +43,11 @@ func LoginPOST(server *server.Server) gin.HandlerFunc {
 		}
 
 		// this is returning 500 for all status codes. This has to get re-worked
-		accessToken, err := server.AuthenticationManager().AuthenticateUser(request.Email, request.Password)
-		if err != nil {
+		accessToken, err := provider.AuthenticateUser(request.Email, request.Password)
+		if errors.Is(err, auth.ErrProviderNotSupported) {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"message": "The authentication provider does not support logging in through the API", "err": err.Error()})
+			return
+		} else if err != nil {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"message": "Failed to generate token", "err": err.Error()})
 			return
 		}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"mtgjson/index"
+	"net/url"
+	"strconv"
+)
+
+/*
+pageFromQuery - Build the requested page of an index from the 'cursor' and 'limit' query parameters. The
+limit defaults to, and is capped at, the value defined in viper under the property 'api.max_page_size'.
+Returns index.ErrInvalidCursor if the cursor cannot be decoded
+*/
+func pageFromQuery(ctx *gin.Context) (index.Page, error) {
+	maxPageSize := viper.GetInt64("api.max_page_size")
+	if maxPageSize <= 0 {
+		maxPageSize = 100
+	}
+
+	limit, err := strconv.ParseInt(ctx.Query("limit"), 10, 64)
+	if err != nil || limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	var offset int64
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		offset, err = index.DecodeCursor(cursor)
+		if err != nil {
+			return index.Page{}, err
+		}
+	}
+
+	return index.Page{Offset: offset, Limit: limit}, nil
+}
+
+/*
+writePageHeaders - Write the total amount of objects in an index to the X-Total-Count header. If objects
+remain after the page, then a Link header pointing to the next page is added, along with the cursor for
+the next page in the X-Next-Cursor header. The Link header is added rather than set so that links written
+by earlier handlers, such as the successor link of a deprecated version, are kept
+*/
+func writePageHeaders(ctx *gin.Context, page index.Page, total int64) {
+	ctx.Header("X-Total-Count", strconv.FormatInt(total, 10))
+
+	if !page.HasNext(total) {
+		return
+	}
+
+	next := page.Next()
+	cursor := index.EncodeCursor(next.Offset)
+
+	query := ctx.Request.URL.Query()
+	query.Set("cursor", cursor)
+	query.Set("limit", strconv.FormatInt(next.Limit, 10))
+
+	nextUrl := url.URL{Path: ctx.Request.URL.Path, RawQuery: query.Encode()}
+
+	ctx.Header("X-Next-Cursor", cursor)
+	ctx.Writer.Header().Add("Link", "<"+nextUrl.String()+">; rel=\"next\"")
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"mtgjson/auth"
+	"net/http"
+)
+
+/*
+authorize - Evaluate ownership and scope policy for the caller performing the action against a resource
+owned by owner. If the caller is denied, then a 403 is written to the response and false is returned
+*/
+func authorize(ctx *gin.Context, resource auth.Resource, action auth.Action, owner string) bool {
+	decision := auth.Authorize(ctx, resource, action, owner)
+	if !decision.Allowed {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": decision.Reason, "requiredScope": decision.RequiredScope})
+		return false
+	}
+
+	return true
+}
+
+/*
+catalogOwner - Return the owner of the cards that a catalog lookup is made against. This is the 'owner' query
+parameter, which defaults to the system catalog rather than the caller
+*/
+func catalogOwner(ctx *gin.Context) string {
+	return ctx.DefaultQuery("owner", auth.SystemOwner)
+}
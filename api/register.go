@@ -7,6 +7,7 @@ import (
 	userModel "github.com/stevezaluk/mtgjson-models/user"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"github.com/stevezaluk/mtgjson-sdk/user"
+	"mtgjson/auth"
 	"net/http"
 )
 
@@ -14,7 +15,7 @@ import (
 RegisterPOST Gin handler for the POST request to the Register Endpoint. This function should not be called
 directly and should only be passed to the gin router. Revalidate this
 */
-func RegisterPOST(server *server.Server) gin.HandlerFunc {
+func RegisterPOST(server *server.Server, provider auth.Provider) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		type RegisterRequest struct {
@@ -36,16 +37,19 @@ func RegisterPOST(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
-		signUpResp, err := server.AuthenticationManager().RegisterUser(request.Username, request.Email, request.Password)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create user in Auth0", "err": err.Error()})
+		userId, err := provider.RegisterUser(request.Username, request.Email, request.Password)
+		if errors.Is(err, auth.ErrProviderNotSupported) {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"message": "The authentication provider does not support registering users through the API", "err": err.Error()})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create user with the authentication provider", "err": err.Error()})
 			return
 		}
 
 		err = user.NewUser(server.Database(), &userModel.User{
 			Username: request.Username,
 			Email:    request.Email,
-			Auth0Id:  signUpResp.ID,
+			Auth0Id:  userId,
 		})
 
 		if errors.Is(err, sdkErrors.ErrInvalidPasswordLength) {
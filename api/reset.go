@@ -15,24 +15,19 @@ import (
 ResetGET Gin handler for the GET request to the Reset Endpoint. This function should not be called
 directly and should only be passed to the gin router
 */
-func ResetGET(server *server.Server) gin.HandlerFunc {
+func ResetGET(server *server.Server, provider auth.Provider) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		userEmail := ctx.GetString("userEmail")
 		email := ctx.DefaultQuery("email", userEmail)
 
-		if email == userEmail {
-			if !auth.ValidateScope(ctx, "read:profile") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to reset your password", "requiredScope": "read:profile"})
-				return
-			}
+		action := auth.ActionWrite
+		if email == userEmail { // resetting your own password only requires access to your own profile
+			action = auth.ActionRead
 		}
 
-		if email != userEmail {
-			if !auth.ValidateScope(ctx, "write:user") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to reset other users passwords", "requiredScope": "write:user"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceUser, action, email) {
+			return
 		}
 
 		_, err := user.GetUser(server.Database(), email)
@@ -44,8 +39,11 @@ func ResetGET(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
-		err = server.AuthenticationManager().ResetUserPassword(email)
-		if err != nil {
+		err = provider.ResetUserPassword(email)
+		if errors.Is(err, auth.ErrProviderNotSupported) {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"message": "The authentication provider does not support resetting passwords through the API", "err": err.Error()})
+			return
+		} else if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed to reset user password", "err": err.Error()})
 			return
 		}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+/*
+Route - A declarative description of a single endpoint on the API. Method is the HTTP method that the
+route responds to, and Path is relative to the Version it is registered under. Scope is the minimum
+scope required to access the route, and if it is left empty then one won't be required. HasAuth
+controls whether a valid token is required to access the route
+*/
+type Route struct {
+	// Method - The HTTP method that this route responds to
+	Method string
+
+	// Path - The path of the route, relative to the prefix of the Version it is registered under
+	Path string
+
+	// Scope - The minimum scope required to access the route. An empty string requires no scope
+	Scope string
+
+	// HasAuth - If set to true, then a valid token is required to access the route
+	HasAuth bool
+
+	// Handler - The handler function that provides the core logic for the route
+	Handler HandlerFunc
+}
+
+/*
+Version - A group of routes served under a common path prefix (ex. /api/v1). Middleware is applied
+to every route within the version, before authentication is validated. If Deprecated is set to true,
+then each response served by this version is returned with a Deprecation header holding DeprecatedAt,
+along with a Sunset header and a successor link if they are provided
+*/
+type Version struct {
+	// Prefix - The path prefix that all routes in this version are served under
+	Prefix string
+
+	// Middleware - Additional gin handlers that are applied to every route in this version
+	Middleware []gin.HandlerFunc
+
+	// Deprecated - If set to true, then a Deprecation header is returned with each response
+	Deprecated bool
+
+	// DeprecatedAt - The date this version was deprecated, returned in the Deprecation header. If this is
+	// zero, then the time the version was registered is used
+	DeprecatedAt time.Time
+
+	// Sunset - An optional HTTP date describing when this version will stop being served
+	Sunset string
+
+	// Successor - The path prefix of the version that clients should migrate to
+	Successor string
+
+	// Routes - The route table for this version
+	Routes []Route
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"github.com/spf13/viper"
+	"log/slog"
+	"net/http"
+)
+
+/*
+Routes - The route table that is shared between all versions of the API. Paths declared here are
+relative to the prefix of the Version they are registered under
+*/
+func (api *API) Routes() []Route {
+	return []Route{
+		{Method: "POST", Path: "/login", Handler: api.withProvider(LoginPOST)},
+		{Method: "POST", Path: "/register", Handler: api.withProvider(RegisterPOST)},
+		{Method: "GET", Path: "/reset", Scope: "read:profile", HasAuth: true, Handler: api.withProvider(ResetGET)},
+
+		{Method: "GET", Path: "/user", Scope: "read:user", HasAuth: true, Handler: UserGET},
+		{Method: "DELETE", Path: "/user", Scope: "write:user", HasAuth: true, Handler: api.withProvider(UserDELETE)},
+
+		{Method: "GET", Path: "/user/apikey", Scope: "read:profile", HasAuth: true, Handler: APIKeyGET},
+		{Method: "POST", Path: "/user/apikey", Scope: "write:profile", HasAuth: true, Handler: APIKeyPOST},
+		{Method: "DELETE", Path: "/user/apikey", Scope: "write:profile", HasAuth: true, Handler: APIKeyDELETE},
+
+		{Method: "GET", Path: "/card", Scope: "read:card.wotc", HasAuth: true, Handler: CardGET},
+		{Method: "GET", Path: "/card/search", Scope: "read:card.wotc", HasAuth: true, Handler: CardSearchGET},
+		{Method: "GET", Path: "/card/autocomplete", Scope: "read:card.wotc", HasAuth: true, Handler: api.withNames(CardAutocompleteGET)},
+		{Method: "GET", Path: "/card/printings", Scope: "read:card.wotc", HasAuth: true, Handler: CardPrintingsGET},
+		{Method: "GET", Path: "/card/price", Scope: "read:card.wotc", HasAuth: true, Handler: CardPriceGET},
+		{Method: "GET", Path: "/card/price/history", Scope: "read:card.wotc", HasAuth: true, Handler: CardPriceHistoryGET},
+		{Method: "POST", Path: "/card/resolve", Scope: "read:card.wotc", HasAuth: true, Handler: CardResolvePOST},
+		{Method: "GET", Path: "/card/named", Scope: "read:card.wotc", HasAuth: true, Handler: api.withNames(CardNamedGET)},
+		{Method: "POST", Path: "/card", Scope: "write:card.user", HasAuth: true, Handler: api.withNames(CardPOST)},
+		{Method: "DELETE", Path: "/card", Scope: "write:card.user", HasAuth: true, Handler: api.withNames(CardDELETE)},
+
+		{Method: "GET", Path: "/deck", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckGET},
+		{Method: "POST", Path: "/deck", Scope: "write:deck.user", HasAuth: true, Handler: DeckPOST},
+		{Method: "DELETE", Path: "/deck", Scope: "write:deck.user", HasAuth: true, Handler: DeckDELETE},
+		{Method: "POST", Path: "/deck/import", Scope: "write:deck.user", HasAuth: true, Handler: api.withNames(DeckImportPOST)},
+		{Method: "POST", Path: "/deck/clone", Scope: "write:deck.user", HasAuth: true, Handler: DeckClonePOST},
+		{Method: "GET", Path: "/deck/provenance", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckProvenanceGET},
+
+		{Method: "GET", Path: "/deck/share", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckShareGET},
+		{Method: "POST", Path: "/deck/share", Scope: "write:deck.user", HasAuth: true, Handler: DeckSharePOST},
+		{Method: "POST", Path: "/deck/share/token", Scope: "write:deck.user", HasAuth: true, Handler: DeckShareTokenPOST},
+		{Method: "DELETE", Path: "/deck/share/token", Scope: "write:deck.user", HasAuth: true, Handler: DeckShareTokenDELETE},
+
+		{Method: "GET", Path: "/deck/price", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckPriceGET},
+		{Method: "GET", Path: "/deck/export", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckExportGET},
+		{Method: "GET", Path: "/deck/validate", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckValidateGET},
+		{Method: "GET", Path: "/deck/stats", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckStatsGET},
+		{Method: "GET", Path: "/deck/draw", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckDrawGET},
+		{Method: "GET", Path: "/deck/simulate", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckSimulateGET},
+		{Method: "GET", Path: "/deck/diff", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckDiffGET},
+
+		{Method: "GET", Path: "/deck/content", Scope: "read:deck.wotc", HasAuth: true, Handler: DeckContentGET},
+		{Method: "POST", Path: "/deck/content", Scope: "write:deck.user", HasAuth: true, Handler: DeckContentPOST},
+		{Method: "DELETE", Path: "/deck/content", Scope: "write:deck.user", HasAuth: true, Handler: DeckContentDELETE},
+
+		{Method: "GET", Path: "/set", Scope: "read:set.wotc", HasAuth: true, Handler: SetGET},
+		{Method: "POST", Path: "/set", Scope: "write:set.user", HasAuth: true, Handler: SetPOST},
+		{Method: "DELETE", Path: "/set", Scope: "write:set.user", HasAuth: true, Handler: SetDELETE},
+
+		{Method: "GET", Path: "/set/content", Scope: "read:set.wotc", HasAuth: true, Handler: SetContentGET},
+		{Method: "POST", Path: "/set/content", Scope: "write:set.user", HasAuth: true, Handler: SetContentPOST},
+		{Method: "DELETE", Path: "/set/content", Scope: "write:set.user", HasAuth: true, Handler: SetContentDELETE},
+	}
+}
+
+/*
+V1 - The first version of the API, served under /api/v1. This version is deprecated in favor of V2
+and each response is returned with a Deprecation header. The deprecation and sunset dates for this
+version are pulled from viper under the properties 'api.v1_deprecation' and 'api.v1_sunset'
+*/
+func (api *API) V1() Version {
+	deprecatedAt, err := http.ParseTime(viper.GetString("api.v1_deprecation"))
+	if err != nil {
+		slog.Warn("Failed to parse api.v1_deprecation as an HTTP date. The time the API was started will be used instead", "err", err)
+	}
+
+	return Version{
+		Prefix:       "/api/v1",
+		Deprecated:   true,
+		DeprecatedAt: deprecatedAt,
+		Sunset:       viper.GetString("api.v1_sunset"),
+		Successor:    "/api/v2",
+		Routes:       api.Routes(),
+	}
+}
+
+/*
+V2 - The current version of the API, served under /api/v2
+*/
+func (api *API) V2() Version {
+	return Version{
+		Prefix: "/api/v2",
+		Routes: api.Routes(),
+	}
+}
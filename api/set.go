@@ -8,6 +8,7 @@ import (
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"github.com/stevezaluk/mtgjson-sdk/set"
 	"mtgjson/auth"
+	"mtgjson/index"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,26 +23,34 @@ func SetGET(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner != "system" && owner != userEmail {
-			if !auth.ValidateScope(ctx, "read:set.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to read other users sets", "requiredScope": "read:user-set"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceSet, auth.ActionRead, owner) {
+			return
 		}
 
 		setCode := ctx.Query("setCode")
 		if setCode == "" {
-			limit := limitToInt64(ctx.DefaultQuery("limit", "100"))
-			results, err := set.IndexSets(server.Database(), limit) // update this to include ownership
-			if errors.Is(err, sdkErrors.ErrNoSet) {
-				ctx.JSON(http.StatusBadRequest, gin.H{"message": "No sets available to index", "err": err.Error()})
+			page, err := pageFromQuery(ctx)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "The cursor passed in the query is not valid", "err": err.Error()})
+				return
+			}
+
+			results, total, err := index.Sets(server, owner, page)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to index sets", "err": err.Error()})
 				return
 			}
 
+			writePageHeaders(ctx, page, total)
 			ctx.JSON(http.StatusOK, results)
 			return
 		}
 
+		if owner == auth.AllOwners {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "An owner of '*' can only be used when listing sets"})
+			return
+		}
+
 		results, err := set.GetSet(server.Database(), setCode, owner)
 		if errors.Is(err, sdkErrors.ErrNoSet) {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find set under the requested Set Code", "err": err.Error(), "setCode": setCode})
@@ -67,18 +76,8 @@ func SetPOST(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:set.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify system or pre-constructed sets", "requiredScope": "write:system-set"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:set.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify another users sets", "requiredScope": "write:user-set"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceSet, auth.ActionWrite, owner) {
+			return
 		}
 
 		var newSet *setModel.Set
@@ -131,18 +130,8 @@ func SetDELETE(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:set.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to delete system or pre-constructed set", "requiredScope": "write:system-set"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:set.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to delete other users sets", "requiredScope": "write:user-set"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceSet, auth.ActionWrite, owner) {
+			return
 		}
 
 		code := ctx.Query("setCode")
@@ -175,11 +164,9 @@ func SetContentGET(server *server.Server) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
-		if owner != "system" && owner != userEmail {
-			if !auth.ValidateScope(ctx, "read:set.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to read other users sets", "requiredScope": "read:user-set"})
-				return
-			}
+
+		if !authorize(ctx, auth.ResourceSet, auth.ActionRead, owner) {
+			return
 		}
 
 		code := ctx.Query("setCode")
@@ -213,18 +200,8 @@ func SetContentPOST(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:set.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify content of system or pre-constructed set", "requiredScope": "write:system-set"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:set.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify content of user owned set", "requiredScope": "write:user-set"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceSet, auth.ActionWrite, owner) {
+			return
 		}
 
 		code := ctx.Query("setCode")
@@ -272,18 +249,8 @@ func SetContentDELETE(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		owner := ctx.DefaultQuery("owner", userEmail)
 
-		if owner == "system" {
-			if !auth.ValidateScope(ctx, "write:set.wotc") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify content of system or pre-constructed sets", "requiredScope": "write:system-set"})
-				return
-			}
-		}
-
-		if owner != userEmail {
-			if !auth.ValidateScope(ctx, "write:set.admin") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to modify content of user owned sets", "requiredScope": "write:user-set"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceSet, auth.ActionWrite, owner) {
+			return
 		}
 
 		code := ctx.Query("setCode")
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"mtgjson/auth"
+	"mtgjson/index"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,21 +22,24 @@ func UserGET(server *server.Server) gin.HandlerFunc {
 		userEmail := ctx.GetString("userEmail")
 		email := ctx.DefaultQuery("email", userEmail)
 
-		if email != userEmail { // externalize logic for fetching own profile to a separate endpoint
-			if !auth.ValidateScope(ctx, "read:user") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to read other user's account data", "requiredScope": "read:user"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceUser, auth.ActionRead, email) {
+			return
 		}
 
 		if email == "" {
-			limit := limitToInt64(ctx.DefaultQuery("limit", "100"))
-			result, err := user.IndexUsers(server.Database(), limit)
+			page, err := pageFromQuery(ctx)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "The cursor passed in the query is not valid", "err": err.Error()})
+				return
+			}
+
+			result, total, err := index.Users(server, page)
 			if err != nil {
-				ctx.JSON(http.StatusNotFound, gin.H{"message": "Failed to find users in database", "err": err.Error()})
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to index users", "err": err.Error()})
 				return
 			}
 
+			writePageHeaders(ctx, page, total)
 			ctx.JSON(http.StatusOK, result)
 			return
 		}
@@ -57,7 +61,7 @@ func UserGET(server *server.Server) gin.HandlerFunc {
 UserDELETE Gin handler for the DELETE request to the User Endpoint. This function should not be called
 directly and should only be passed to the gin router
 */
-func UserDELETE(server *server.Server) gin.HandlerFunc {
+func UserDELETE(server *server.Server, provider auth.Provider) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		userEmail := ctx.GetString("userEmail")
@@ -68,11 +72,8 @@ func UserDELETE(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
-		if email != userEmail {
-			if !auth.ValidateScope(ctx, "write:user") {
-				ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid permissions to delete other users", "requiredScope": "write:user"})
-				return
-			}
+		if !authorize(ctx, auth.ResourceUser, auth.ActionWrite, email) {
+			return
 		}
 
 		requestedUser, err := user.GetUser(server.Database(), email)
@@ -94,9 +95,12 @@ func UserDELETE(server *server.Server) gin.HandlerFunc {
 		}
 
 		// this is returning 500 for any response. This needs to change
-		err = server.AuthenticationManager().DeactivateUser("auth0|" + requestedUser.Auth0Id)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to remove user from Auth0", "err": err.Error()})
+		err = provider.DeactivateUser(requestedUser.Auth0Id)
+		if errors.Is(err, auth.ErrProviderNotSupported) {
+			ctx.JSON(http.StatusOK, gin.H{"message": "Successfully deleted user account. The user must be deactivated with the authentication provider directly"})
+			return
+		} else if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to deactivate user with the authentication provider", "err": err.Error()})
 			return
 		}
 
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+	"time"
+)
+
+// apiKeyCollection - The MongoDB collection that API keys are stored in
+const apiKeyCollection = "apikey"
+
+// apiKeyPrefix - The prefix of every raw API key, used to make leaked keys easy to identify
+const apiKeyPrefix = "mtg"
+
+var (
+	// ErrInvalidAPIKey - Returned when an API key is malformed, does not exist, has been revoked, or has expired
+	ErrInvalidAPIKey = errors.New("auth: API key is invalid, revoked or expired")
+
+	// ErrNoAPIKey - Returned when an API key cannot be found under the requested ID and owner
+	ErrNoAPIKey = errors.New("auth: failed to find API key")
+
+	// ErrAPIKeyScopeNotHeld - Returned when an API key is requested with a scope that the caller does not hold
+	ErrAPIKeyScopeNotHeld = errors.New("auth: API keys can only be granted scopes that the caller holds")
+)
+
+/*
+APIKey - A personal access token that a user can use in place of a JWT for automation. Only the SHA-256
+hash of the key is stored, so the raw key is only ever returned once, when it is created
+*/
+type APIKey struct {
+	// KeyId - The public identifier of the key. This is embedded in the raw key
+	KeyId string `json:"keyId" bson:"keyId"`
+
+	// Name - A human-readable name for the key
+	Name string `json:"name" bson:"name"`
+
+	// Owner - The email address of the user that owns the key
+	Owner string `json:"owner" bson:"owner"`
+
+	// Scope - A space separated string of the scopes granted to the key
+	Scope string `json:"scope" bson:"scope"`
+
+	// Hash - The hex encoded SHA-256 hash of the raw key
+	Hash string `json:"-" bson:"hash"`
+
+	// CreationDate - The time that the key was created
+	CreationDate time.Time `json:"creationDate" bson:"creationDate"`
+
+	// ExpiresAt - The time that the key expires. A nil value means the key never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+
+	// Revoked - Set to true once the key has been revoked
+	Revoked bool `json:"revoked" bson:"revoked"`
+}
+
+/*
+HasScope Validates that the expected scope has been granted to the key
+*/
+func (key APIKey) HasScope(expectedScope string) bool {
+	return CustomClaims{Scope: key.Scope}.HasScope(expectedScope)
+}
+
+/*
+randomString Return a URL safe string of n random bytes
+*/
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+/*
+hashAPIKey Return the hex encoded SHA-256 hash of a raw API key
+*/
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+NewAPIKey Create a new API key owned by the owner parameter, and granted the scopes in the scope parameter.
+Each requested scope must be held by the caller, whose scopes are passed in the callerScope parameter. If
+ttl is zero, then the key never expires. The raw key is returned along with the stored key, and cannot
+be recovered after this
+*/
+func NewAPIKey(server *server.Server, owner string, name string, scope string, callerScope string, ttl time.Duration) (string, *APIKey, error) {
+	caller := CustomClaims{Scope: callerScope}
+	for _, requested := range strings.Fields(scope) {
+		if !caller.HasScope(requested) {
+			return "", nil, ErrAPIKeyScopeNotHeld
+		}
+	}
+
+	id := make([]byte, 8)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", nil, err
+	}
+	keyId := hex.EncodeToString(id)
+
+	secret, err := randomString(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rawKey := apiKeyPrefix + "_" + keyId + "_" + secret
+
+	key := &APIKey{
+		KeyId:        keyId,
+		Name:         name,
+		Owner:        owner,
+		Scope:        strings.Join(strings.Fields(scope), " "),
+		Hash:         hashAPIKey(rawKey),
+		CreationDate: time.Now().UTC(),
+	}
+
+	if ttl > 0 {
+		expiresAt := key.CreationDate.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	_, err = server.Database().Database().Collection(apiKeyCollection).InsertOne(context.Background(), key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return rawKey, key, nil
+}
+
+/*
+IndexAPIKeys Fetch all API keys owned by the owner parameter, including revoked and expired keys
+*/
+func IndexAPIKeys(server *server.Server, owner string) ([]*APIKey, error) {
+	ctx := context.Background()
+
+	cursor, err := server.Database().Database().Collection(apiKeyCollection).Find(
+		ctx,
+		bson.M{"owner": owner},
+		options.Find().SetSort(bson.D{{Key: "creationDate", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []*APIKey{}
+
+	err = cursor.All(ctx, &keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+/*
+RevokeAPIKey Revoke the API key with the passed ID owned by the owner parameter. Revoked keys are kept
+so that they continue to appear when listing keys
+*/
+func RevokeAPIKey(server *server.Server, keyId string, owner string) error {
+	result, err := server.Database().Database().Collection(apiKeyCollection).UpdateOne(
+		context.Background(),
+		bson.M{"keyId": keyId, "owner": owner},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNoAPIKey
+	}
+
+	return nil
+}
+
+/*
+ValidateAPIKey Validate a raw API key, returning the stored key if it exists, has not been revoked, and
+has not expired. Otherwise ErrInvalidAPIKey is returned
+*/
+func ValidateAPIKey(server *server.Server, rawKey string) (*APIKey, error) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return nil, ErrInvalidAPIKey
+	}
+
+	var key APIKey
+
+	err := server.Database().Database().Collection(apiKeyCollection).FindOne(
+		context.Background(),
+		bson.M{"keyId": parts[1]},
+	).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInvalidAPIKey
+	} else if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(rawKey)), []byte(key.Hash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.Revoked || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return &key, nil
+}
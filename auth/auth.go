@@ -2,9 +2,7 @@ package auth
 
 import (
 	"net/url"
-	"time"
 
-	"github.com/auth0/go-jwt-middleware/v2/jwks"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/spf13/viper"
 )
@@ -23,22 +21,23 @@ func GetIssuerUrl() *url.URL {
 }
 
 /*
-GetTokenValidator Creates a new JWT token validator for use within the ValidateToken middleware. The object that
-this function returns provides logic for validating JWT tokens and unmarshalling custom claims
-defined in your Auth0 tenant
+NewTokenValidator Creates a new JWT token validator for use within the ValidateToken middleware. The object that
+this function returns provides logic for validating JWT tokens issued by the passed authentication provider
+and unmarshalling custom claims. The name of the claim that the caller's email address is read from is
+pulled from viper under the property 'auth.email_claim'. The validator is safe for concurrent use, and
+should be created once at startup and shared across requests
 */
-func GetTokenValidator() (*validator.Validator, error) {
-	issuer := GetIssuerUrl()
-	provider := jwks.NewCachingProvider(issuer, 5*time.Minute)
+func NewTokenValidator(provider Provider) (*validator.Validator, error) {
+	emailClaim := viper.GetString("auth.email_claim")
 
 	tokenValidator, err := validator.New(
 		provider.KeyFunc,
-		validator.RS256,
-		issuer.String(),
-		[]string{viper.GetString("auth0.audience")},
+		provider.Algorithm(),
+		provider.IssuerUrl().String(),
+		provider.Audience(),
 		validator.WithCustomClaims(
 			func() validator.CustomClaims {
-				return &CustomClaims{}
+				return NewCustomClaims(emailClaim)
 			},
 		),
 	)
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/spf13/viper"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"net/url"
+	"time"
+)
+
+/*
+Auth0Provider - An authentication provider backed by an Auth0 tenant. Account operations are delegated
+to the authentication manager of the server structure, and keys are fetched from the JWKS of the tenant
+*/
+type Auth0Provider struct {
+	// server - The server structure whose authentication manager is used for account operations
+	server *server.Server
+
+	// keyProvider - The caching JWKS provider for the tenant
+	keyProvider *jwks.CachingProvider
+}
+
+/*
+NewAuth0Provider - A constructor for the Auth0Provider structure. Keys are cached for the amount of
+minutes defined in viper under the property 'auth.jwks_cache_ttl', and are refreshed in the background
+once this expires
+*/
+func NewAuth0Provider(server *server.Server) *Auth0Provider {
+	ttl := time.Duration(viper.GetInt("auth.jwks_cache_ttl")) * time.Minute
+
+	return &Auth0Provider{
+		server:      server,
+		keyProvider: jwks.NewCachingProvider(GetIssuerUrl(), ttl),
+	}
+}
+
+/*
+Algorithm - Return the algorithm that access tokens issued by the Auth0 tenant are signed with
+*/
+func (provider *Auth0Provider) Algorithm() validator.SignatureAlgorithm {
+	return validator.RS256
+}
+
+/*
+IssuerUrl - Return the issuer URL of the Auth0 tenant
+*/
+func (provider *Auth0Provider) IssuerUrl() *url.URL {
+	return GetIssuerUrl()
+}
+
+/*
+Audience - Return the audience of the Auth0 API. This is pulled from viper under the property 'auth0.audience'
+*/
+func (provider *Auth0Provider) Audience() []string {
+	return []string{viper.GetString("auth0.audience")}
+}
+
+/*
+KeyFunc - Return the cached JWKS of the Auth0 tenant
+*/
+func (provider *Auth0Provider) KeyFunc(ctx context.Context) (interface{}, error) {
+	return provider.keyProvider.KeyFunc(ctx)
+}
+
+/*
+GetEmailFromToken - Fetch the email address of the user the token was issued to from Auth0
+*/
+func (provider *Auth0Provider) GetEmailFromToken(token string) (string, error) {
+	return provider.server.AuthenticationManager().GetEmailFromToken(token)
+}
+
+/*
+AuthenticateUser - Exchange an email address and password for an access token using Auth0
+*/
+func (provider *Auth0Provider) AuthenticateUser(email string, password string) (interface{}, error) {
+	token, err := provider.server.AuthenticationManager().AuthenticateUser(email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+/*
+RegisterUser - Create a new user in Auth0, returning the ID that Auth0 assigned them
+*/
+func (provider *Auth0Provider) RegisterUser(username string, email string, password string) (string, error) {
+	signUpResp, err := provider.server.AuthenticationManager().RegisterUser(username, email, password)
+	if err != nil {
+		return "", err
+	}
+
+	return signUpResp.ID, nil
+}
+
+/*
+ResetUserPassword - Send a password reset email to the user through Auth0
+*/
+func (provider *Auth0Provider) ResetUserPassword(email string) error {
+	return provider.server.AuthenticationManager().ResetUserPassword(email)
+}
+
+/*
+DeactivateUser - Block the user from authenticating with Auth0. The userId parameter should be the
+ID that Auth0 assigned the user during registration
+*/
+func (provider *Auth0Provider) DeactivateUser(userId string) error {
+	return provider.server.AuthenticationManager().DeactivateUser("auth0|" + userId)
+}
@@ -2,14 +2,54 @@ package auth
 
 import (
 	"context"
-	"strings"
+	"encoding/json"
 )
 
 /*
-CustomClaims Struct for unmarshalling Auth0 scopes during token validation
+CustomClaims Struct for unmarshalling Auth0 scopes during token validation. If an email claim is
+configured, then the caller's email address is read from this claim and stored in Email
 */
 type CustomClaims struct {
 	Scope string `json:"scope"`
+
+	// Email - The email address of the caller, read from the claim named by emailClaim
+	Email string `json:"-"`
+
+	// emailClaim - The name of the claim that the caller's email address is read from. This is
+	// commonly a namespaced custom claim (ex. https://mtgjson.example/email)
+	emailClaim string
+}
+
+/*
+NewCustomClaims Create a new CustomClaims struct that reads the caller's email address from the claim
+defined by the emailClaim parameter. If emailClaim is empty, then the email is not read from the token
+*/
+func NewCustomClaims(emailClaim string) *CustomClaims {
+	return &CustomClaims{emailClaim: emailClaim}
+}
+
+/*
+UnmarshalJSON Unmarshal the scope claim and, if configured, the email claim from the token payload
+*/
+func (c *CustomClaims) UnmarshalJSON(data []byte) error {
+	var claims map[string]interface{}
+
+	err := json.Unmarshal(data, &claims)
+	if err != nil {
+		return err
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		c.Scope = scope
+	}
+
+	if c.emailClaim != "" {
+		if email, ok := claims[c.emailClaim].(string); ok {
+			c.Email = email
+		}
+	}
+
+	return nil
 }
 
 /*
@@ -21,15 +61,9 @@ func (c CustomClaims) Validate(ctx context.Context) error {
 }
 
 /*
-HasScope Validates that the expected scope exists within the CustomClaims struct
+HasScope Validates that the expected scope exists within the CustomClaims struct, either directly
+or because it is implied by another scope that is held
 */
 func (c CustomClaims) HasScope(expectedScope string) bool {
-	result := strings.Split(c.Scope, " ")
-	for i := range result {
-		if result[i] == expectedScope {
-			return true
-		}
-	}
-
-	return false
+	return ExpandScope(c.Scope)[expectedScope]
 }
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+/*
+identityEntry A single cached identity, along with the time that it expires at
+*/
+type identityEntry struct {
+	email     string
+	expiresAt time.Time
+}
+
+/*
+IdentityCache A TTL cache mapping access tokens to the email address of the caller. This is used
+as a fallback when the caller's email is not present in the token claims, so that the authentication
+provider is only asked once per token. Tokens are hashed before being used as a key
+*/
+type IdentityCache struct {
+	// ttl - The maximum amount of time an identity is cached for
+	ttl time.Duration
+
+	// mutex - Guards entries
+	mutex sync.Mutex
+
+	// entries - The cached identities, keyed by the SHA-256 hash of the token
+	entries map[string]identityEntry
+}
+
+/*
+NewIdentityCache A constructor for the IdentityCache structure. The ttl parameter is the maximum
+amount of time that an identity is cached for
+*/
+func NewIdentityCache(ttl time.Duration) *IdentityCache {
+	return &IdentityCache{
+		ttl:     ttl,
+		entries: make(map[string]identityEntry),
+	}
+}
+
+/*
+hashToken Return the hex encoded SHA-256 hash of the passed token
+*/
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+Get Fetch the email address cached for the passed token. Returns false if the token is not cached
+or if its entry has expired
+*/
+func (cache *IdentityCache) Get(token string) (string, bool) {
+	key := hashToken(token)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(cache.entries, key)
+		return "", false
+	}
+
+	return entry.email, true
+}
+
+/*
+Set Cache the email address for the passed token. The entry expires after the cache's TTL, or at
+tokenExpiry if the token expires before then. Expired entries are swept each time an entry is set
+*/
+func (cache *IdentityCache) Set(token string, email string, tokenExpiry time.Time) {
+	now := time.Now()
+
+	expiresAt := now.Add(cache.ttl)
+	if !tokenExpiry.IsZero() && tokenExpiry.Before(expiresAt) {
+		expiresAt = tokenExpiry
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for key, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+
+	cache.entries[hashToken(token)] = identityEntry{email: email, expiresAt: expiresAt}
+}
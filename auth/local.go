@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"gopkg.in/go-jose/go-jose.v2"
+	"gopkg.in/go-jose/go-jose.v2/jwt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+/*
+LocalProvider - An authentication provider that signs its own access tokens, intended for offline
+development and integration testing. Tokens are signed with either RS256 using a private key stored on
+disk, or HS256 using a shared secret. The email address of the caller is stored in the subject claim,
+and in the claim named by the property 'auth.email_claim' if it is set
+*/
+type LocalProvider struct {
+	// algorithm - The algorithm tokens are signed with. Either RS256 or HS256
+	algorithm validator.SignatureAlgorithm
+
+	// signingKey - The key tokens are signed with. Either a *rsa.PrivateKey or a []byte secret
+	signingKey interface{}
+
+	// verificationKey - The key tokens are verified with. Either a *rsa.PublicKey or a []byte secret
+	verificationKey interface{}
+
+	// issuer - The issuer written to and expected in each token
+	issuer *url.URL
+
+	// audience - The audience written to and expected in each token
+	audience string
+}
+
+/*
+NewLocalProvider - A constructor for the LocalProvider structure. If the algorithm is RS256, then the
+private key is read from keyPath, and is generated and written to keyPath if the file does not exist.
+If the algorithm is HS256, then tokens are signed with the secret parameter
+*/
+func NewLocalProvider(algorithm string, keyPath string, secret string, issuer string, audience string) (*LocalProvider, error) {
+	issuerUrl, err := url.Parse(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &LocalProvider{
+		algorithm: validator.SignatureAlgorithm(algorithm),
+		issuer:    issuerUrl,
+		audience:  audience,
+	}
+
+	switch provider.algorithm {
+	case validator.RS256:
+		privateKey, err := loadOrGenerateKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		provider.signingKey = privateKey
+		provider.verificationKey = &privateKey.PublicKey
+	case validator.HS256:
+		if len(secret) < 32 {
+			return nil, errors.New("auth: the local HS256 secret must be at least 32 characters")
+		}
+
+		provider.signingKey = []byte(secret)
+		provider.verificationKey = []byte(secret)
+	default:
+		return nil, fmt.Errorf("auth: unsupported local signing algorithm '%s'. Must be RS256 or HS256", algorithm)
+	}
+
+	return provider, nil
+}
+
+/*
+LocalProviderFromConfig - Initialize the LocalProvider structure using values from viper. Values are
+pulled from the properties under 'auth.local'
+*/
+func LocalProviderFromConfig() (*LocalProvider, error) {
+	return NewLocalProvider(
+		viper.GetString("auth.local.algorithm"),
+		viper.GetString("auth.local.key_path"),
+		viper.GetString("auth.local.secret"),
+		viper.GetString("auth.local.issuer"),
+		viper.GetString("auth.local.audience"),
+	)
+}
+
+/*
+loadOrGenerateKey - Read a PEM encoded RSA private key from path. A leading ~ in path is expanded to the
+home directory of the user. If the file does not exist, then a new 2048-bit key is generated and written to
+path so that it survives restarts
+*/
+func loadOrGenerateKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return nil, errors.New("auth: a key path is required for RS256 local tokens")
+	}
+
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+		if err != nil {
+			return nil, err
+		}
+
+		block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}
+		err = os.WriteFile(path, pem.EncodeToMemory(block), 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		return privateKey, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("auth: no PEM data found in '%s'", path)
+	}
+
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("auth: key in '%s' is not an RSA private key", path)
+	}
+
+	return privateKey, nil
+}
+
+/*
+MintToken - Sign a new access token for the passed email address, carrying the passed space separated
+scopes. The token expires after ttl
+*/
+func (provider *LocalProvider) MintToken(email string, scope string, ttl time.Duration) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(provider.algorithm), Key: provider.signingKey},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	registered := jwt.Claims{
+		Issuer:   provider.issuer.String(),
+		Subject:  email,
+		Audience: jwt.Audience{provider.audience},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(ttl)),
+	}
+
+	custom := map[string]interface{}{"scope": scope}
+	if emailClaim := viper.GetString("auth.email_claim"); emailClaim != "" {
+		custom[emailClaim] = email
+	}
+
+	return jwt.Signed(signer).Claims(registered).Claims(custom).CompactSerialize()
+}
+
+/*
+Algorithm - Return the algorithm that local tokens are signed with
+*/
+func (provider *LocalProvider) Algorithm() validator.SignatureAlgorithm {
+	return provider.algorithm
+}
+
+/*
+IssuerUrl - Return the issuer that local tokens are signed with
+*/
+func (provider *LocalProvider) IssuerUrl() *url.URL {
+	return provider.issuer
+}
+
+/*
+Audience - Return the audience that local tokens are signed with
+*/
+func (provider *LocalProvider) Audience() []string {
+	return []string{provider.audience}
+}
+
+/*
+KeyFunc - Return the key that local tokens are verified with
+*/
+func (provider *LocalProvider) KeyFunc(ctx context.Context) (interface{}, error) {
+	return provider.verificationKey, nil
+}
+
+/*
+GetEmailFromToken - Return the subject of the token, which holds the caller's email address for local
+tokens. This should only be called with tokens that have already been validated
+*/
+func (provider *LocalProvider) GetEmailFromToken(token string) (string, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return "", err
+	}
+
+	var claims jwt.Claims
+
+	err = parsed.Claims(provider.verificationKey, &claims)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
+}
+
+/*
+AuthenticateUser - Not supported by the local provider, as it does not store passwords. Tokens should be
+minted with the mint-token command instead. Always returns ErrProviderNotSupported
+*/
+func (provider *LocalProvider) AuthenticateUser(email string, password string) (interface{}, error) {
+	return nil, ErrProviderNotSupported
+}
+
+/*
+RegisterUser - Return a random ID for the new user. Passwords are not stored by the local provider
+*/
+func (provider *LocalProvider) RegisterUser(username string, email string, password string) (string, error) {
+	id := make([]byte, 12)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+/*
+ResetUserPassword - Not supported by the local provider, as it does not store passwords. Always returns
+ErrProviderNotSupported
+*/
+func (provider *LocalProvider) ResetUserPassword(email string) error {
+	return ErrProviderNotSupported
+}
+
+/*
+DeactivateUser - Does nothing, as the local provider does not store user accounts
+*/
+func (provider *LocalProvider) DeactivateUser(userId string) error {
+	return nil
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+oidcDiscovery - The subset of an OpenID Connect discovery document that the OIDCProvider depends on
+*/
+type oidcDiscovery struct {
+	Issuer                           string   `json:"issuer"`
+	JwksUri                          string   `json:"jwks_uri"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint"`
+	IdTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+/*
+oidcAlgorithms - The signing algorithms that tokens from an OpenID Connect provider can be validated with.
+Symmetric algorithms are excluded as the verification keys are fetched from the public JWKS of the provider
+*/
+var oidcAlgorithms = map[string]validator.SignatureAlgorithm{
+	"RS256": validator.RS256,
+	"RS384": validator.RS384,
+	"RS512": validator.RS512,
+	"PS256": validator.PS256,
+	"PS384": validator.PS384,
+	"PS512": validator.PS512,
+	"ES256": validator.ES256,
+	"ES384": validator.ES384,
+	"ES512": validator.ES512,
+	"EdDSA": validator.EdDSA,
+}
+
+/*
+signingAlgorithm - Return the algorithm that access tokens from an OpenID Connect provider are validated with.
+If override is not empty, then it is used regardless of the discovery document. Otherwise RS256 is returned
+if the provider lists it in id_token_signing_alg_values_supported, or does not list any algorithms, as it is
+the default of the OpenID Connect specification and what most providers (ex. Keycloak) sign access tokens
+with. If RS256 is not listed, then the first listed algorithm that tokens can be validated with is returned
+*/
+func signingAlgorithm(override string, supported []string) (validator.SignatureAlgorithm, error) {
+	if override != "" {
+		algorithm, ok := oidcAlgorithms[override]
+		if !ok {
+			return "", fmt.Errorf("auth: unsupported OIDC signing algorithm '%s'", override)
+		}
+
+		return algorithm, nil
+	}
+
+	if len(supported) == 0 || slices.Contains(supported, string(validator.RS256)) {
+		return validator.RS256, nil
+	}
+
+	for _, name := range supported {
+		if algorithm, ok := oidcAlgorithms[name]; ok {
+			return algorithm, nil
+		}
+	}
+
+	return "", fmt.Errorf("auth: none of the signing algorithms supported by the provider (%s) can be validated", strings.Join(supported, ", "))
+}
+
+/*
+OIDCProvider - An authentication provider backed by any OpenID Connect compliant identity provider
+(ex. Keycloak or Dex). The endpoints of the provider are resolved using OpenID Connect discovery.
+Registering, resetting passwords and deactivating users are not part of the OpenID Connect
+specification, and should be done through the identity provider directly
+*/
+type OIDCProvider struct {
+	// issuer - The issuer URL of the identity provider
+	issuer *url.URL
+
+	// audience - The audiences that access tokens must be issued for
+	audience []string
+
+	// clientId - The client ID used for the password grant during login
+	clientId string
+
+	// clientSecret - The client secret used for the password grant during login
+	clientSecret string
+
+	// scope - The scopes requested during login
+	scope string
+
+	// discovery - The discovery document fetched from the identity provider
+	discovery oidcDiscovery
+
+	// algorithm - The algorithm access tokens are signed with. If this is empty when the provider is
+	// constructed, then it is selected from the discovery document
+	algorithm validator.SignatureAlgorithm
+
+	// keyProvider - The caching JWKS provider for the identity provider
+	keyProvider *jwks.CachingProvider
+
+	// client - The HTTP client used for requests to the identity provider
+	client *http.Client
+}
+
+/*
+NewOIDCProvider - A constructor for the OIDCProvider structure. The discovery document is fetched from
+the well-known endpoint of the issuer, and an error is returned if it cannot be fetched or if the issuer
+it declares does not match the issuer parameter. If algorithm is empty, then the algorithm tokens are
+validated with is selected from the discovery document
+*/
+func NewOIDCProvider(issuer string, audience []string, clientId string, clientSecret string, scope string, algorithm string, jwksCacheTtl time.Duration) (*OIDCProvider, error) {
+	issuerUrl, err := url.Parse(strings.TrimSuffix(issuer, "/") + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &OIDCProvider{
+		issuer:       issuerUrl,
+		audience:     audience,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		scope:        scope,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+
+	err = provider.discover(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	jwksUri, err := url.Parse(provider.discovery.JwksUri)
+	if err != nil {
+		return nil, fmt.Errorf("auth: invalid jwks_uri in discovery document: %w", err)
+	}
+
+	provider.keyProvider = jwks.NewCachingProvider(issuerUrl, jwksCacheTtl, jwks.WithCustomJWKSURI(jwksUri))
+
+	return provider, nil
+}
+
+/*
+OIDCProviderFromConfig - Initialize the OIDCProvider structure using values from viper. Values are pulled
+from the properties under 'auth.oidc'
+*/
+func OIDCProviderFromConfig() (*OIDCProvider, error) {
+	return NewOIDCProvider(
+		viper.GetString("auth.oidc.issuer"),
+		[]string{viper.GetString("auth.oidc.audience")},
+		viper.GetString("auth.oidc.client_id"),
+		viper.GetString("auth.oidc.client_secret"),
+		viper.GetString("auth.oidc.scope"),
+		viper.GetString("auth.oidc.algorithm"),
+		time.Duration(viper.GetInt("auth.jwks_cache_ttl"))*time.Minute,
+	)
+}
+
+/*
+discover - Fetch the discovery document from the well-known endpoint of the issuer, and select the algorithm
+tokens are validated with. If algorithm is not empty, then it overrides the algorithms the document declares
+*/
+func (provider *OIDCProvider) discover(algorithm string) error {
+	wellKnown := provider.issuer.JoinPath(".well-known", "openid-configuration")
+
+	resp, err := provider.client.Get(wellKnown.String())
+	if err != nil {
+		return fmt.Errorf("auth: failed to fetch discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth: failed to fetch discovery document: unexpected status %d", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&provider.discovery)
+	if err != nil {
+		return fmt.Errorf("auth: failed to decode discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(provider.discovery.Issuer, "/") != strings.TrimSuffix(provider.issuer.String(), "/") {
+		return fmt.Errorf("auth: discovery document issuer '%s' does not match '%s'", provider.discovery.Issuer, provider.issuer.String())
+	}
+
+	if provider.discovery.JwksUri == "" {
+		return errors.New("auth: discovery document is missing a jwks_uri")
+	}
+
+	provider.algorithm, err = signingAlgorithm(algorithm, provider.discovery.IdTokenSigningAlgValuesSupported)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+Algorithm - Return the algorithm that access tokens issued by the identity provider are signed with
+*/
+func (provider *OIDCProvider) Algorithm() validator.SignatureAlgorithm {
+	return provider.algorithm
+}
+
+/*
+IssuerUrl - Return the issuer URL of the identity provider, exactly as it is declared in the discovery document
+*/
+func (provider *OIDCProvider) IssuerUrl() *url.URL {
+	issuer, err := url.Parse(provider.discovery.Issuer)
+	if err != nil {
+		return provider.issuer
+	}
+
+	return issuer
+}
+
+/*
+Audience - Return the audiences that access tokens must be issued for
+*/
+func (provider *OIDCProvider) Audience() []string {
+	return provider.audience
+}
+
+/*
+KeyFunc - Return the cached JWKS of the identity provider
+*/
+func (provider *OIDCProvider) KeyFunc(ctx context.Context) (interface{}, error) {
+	return provider.keyProvider.KeyFunc(ctx)
+}
+
+/*
+GetEmailFromToken - Fetch the email address of the user the token was issued to from the userinfo
+endpoint of the identity provider
+*/
+func (provider *OIDCProvider) GetEmailFromToken(token string) (string, error) {
+	if provider.discovery.UserinfoEndpoint == "" {
+		return "", ErrProviderNotSupported
+	}
+
+	req, err := http.NewRequest(http.MethodGet, provider.discovery.UserinfoEndpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := provider.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("auth: userinfo request failed with status %d", resp.StatusCode)
+	}
+
+	var userinfo struct {
+		Email string `json:"email"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&userinfo)
+	if err != nil {
+		return "", err
+	}
+
+	if userinfo.Email == "" {
+		return "", errors.New("auth: userinfo response does not contain an email")
+	}
+
+	return userinfo.Email, nil
+}
+
+/*
+AuthenticateUser - Exchange an email address and password for an access token using the resource owner
+password grant. The client must be permitted to use this grant by the identity provider
+*/
+func (provider *OIDCProvider) AuthenticateUser(email string, password string) (interface{}, error) {
+	if provider.discovery.TokenEndpoint == "" {
+		return nil, ErrProviderNotSupported
+	}
+
+	form := url.Values{
+		"grant_type":    {"password"},
+		"username":      {email},
+		"password":      {password},
+		"client_id":     {provider.clientId},
+		"client_secret": {provider.clientSecret},
+	}
+
+	if provider.scope != "" {
+		form.Set("scope", provider.scope)
+	}
+
+	if len(provider.audience) != 0 && provider.audience[0] != "" {
+		form.Set("audience", provider.audience[0])
+	}
+
+	resp, err := provider.client.PostForm(provider.discovery.TokenEndpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token map[string]interface{}
+
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth: token request failed with status %d: %v", resp.StatusCode, token["error_description"])
+	}
+
+	return token, nil
+}
+
+/*
+RegisterUser - Not supported by generic OpenID Connect providers. Always returns ErrProviderNotSupported
+*/
+func (provider *OIDCProvider) RegisterUser(username string, email string, password string) (string, error) {
+	return "", ErrProviderNotSupported
+}
+
+/*
+ResetUserPassword - Not supported by generic OpenID Connect providers. Always returns ErrProviderNotSupported
+*/
+func (provider *OIDCProvider) ResetUserPassword(email string) error {
+	return ErrProviderNotSupported
+}
+
+/*
+DeactivateUser - Not supported by generic OpenID Connect providers. Always returns ErrProviderNotSupported
+*/
+func (provider *OIDCProvider) DeactivateUser(userId string) error {
+	return ErrProviderNotSupported
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+)
+
+func TestSigningAlgorithm(t *testing.T) {
+	keycloak := []string{"PS384", "RS384", "EdDSA", "ES384", "HS256", "HS512", "ES256", "RS256", "HS384", "ES512", "PS256", "PS512", "RS512"}
+
+	tests := []struct {
+		name      string
+		override  string
+		supported []string
+		want      validator.SignatureAlgorithm
+		err       bool
+	}{
+		{"not declared", "", nil, validator.RS256, false},
+		{"single algorithm", "", []string{"ES256"}, validator.ES256, false},
+		{"rs256 is preferred", "", []string{"PS256", "RS256"}, validator.RS256, false},
+		{"keycloak", "", keycloak, validator.RS256, false},
+		{"first supported without rs256", "", []string{"PS256", "ES256"}, validator.PS256, false},
+		{"symmetric algorithms are skipped", "", []string{"HS256", "RS512"}, validator.RS512, false},
+		{"unsupported algorithms are skipped", "", []string{"none", "EdDSA"}, validator.EdDSA, false},
+		{"nothing supported", "", []string{"HS256", "none"}, "", true},
+		{"override", "ES256", keycloak, validator.ES256, false},
+		{"override not declared", "PS512", []string{"RS256"}, validator.PS512, false},
+		{"symmetric override", "HS256", keycloak, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := signingAlgorithm(test.override, test.supported)
+			if (err != nil) != test.err {
+				t.Fatalf("signingAlgorithm(%q, %v) returned error %v", test.override, test.supported, err)
+			}
+
+			if got != test.want {
+				t.Errorf("signingAlgorithm(%q, %v) = %q, want %q", test.override, test.supported, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// SystemOwner - The owner of objects released by Wizards of the Coast (ex. pre-constructed decks)
+const SystemOwner = "system"
+
+// AllOwners - Passed as the owner when listing objects regardless of who owns them. Only admins may do this
+const AllOwners = "*"
+
+/*
+Resource - A type of object that ownership and scope policy is evaluated for
+*/
+type Resource string
+
+const (
+	ResourceCard Resource = "card"
+	ResourceDeck Resource = "deck"
+	ResourceSet  Resource = "set"
+	ResourceUser Resource = "user"
+)
+
+/*
+Action - An operation performed against a Resource
+*/
+type Action string
+
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+)
+
+/*
+Decision - The result of evaluating policy for a caller. RequiredScope is the space separated list of
+scopes needed to perform the action, and if Allowed is false, then Reason is a human-readable explanation
+suitable for a response
+*/
+type Decision struct {
+	// Allowed - Set to true if the caller may perform the action
+	Allowed bool
+
+	// RequiredScope - The space separated scopes required to perform the action
+	RequiredScope string
+
+	// Reason - A human-readable explanation of why the action was denied
+	Reason string
+}
+
+/*
+scopeImplications - Declares the scopes that are implicitly granted by holding another scope. Admin
+scopes imply the user scope for the same resource, and admin read scopes also imply reading objects
+owned by the system, as they grant access to every object stored in the database. Likewise, the user
+scopes grant access to every account, so they imply the profile scope for the caller's own account
+*/
+var scopeImplications = map[string][]string{
+	"read:card.admin":  {"read:card.user", "read:card.wotc"},
+	"read:deck.admin":  {"read:deck.user", "read:deck.wotc"},
+	"read:set.admin":   {"read:set.user", "read:set.wotc"},
+	"write:card.admin": {"write:card.user"},
+	"write:deck.admin": {"write:deck.user"},
+	"write:set.admin":  {"write:set.user"},
+	"read:user":        {"read:profile"},
+	"write:user":       {"write:profile"},
+}
+
+/*
+ExpandScope Return the set of scopes held by a caller with the passed space separated scope string,
+including any scopes implied by the ones held
+*/
+func ExpandScope(scope string) map[string]bool {
+	expanded := make(map[string]bool)
+
+	var expand func(scope string)
+	expand = func(scope string) {
+		if expanded[scope] {
+			return
+		}
+
+		expanded[scope] = true
+		for _, implied := range scopeImplications[scope] {
+			expand(implied)
+		}
+	}
+
+	for _, held := range strings.Fields(scope) {
+		expand(held)
+	}
+
+	return expanded
+}
+
+/*
+RequiredScopes Return every scope needed to perform the action against a resource owned by owner. Reading
+objects owned by the system or by the caller only requires the 'wotc' read scope that read routes are already
+protected by, and modifying the caller's own objects requires the 'user' write scope. Modifying objects owned
+by the system requires both the 'wotc' and the 'admin' write scope, and objects owned by any other user
+(including AllOwners) require the 'admin' scope. User accounts are never owned by the system, so the caller's
+own account requires the 'profile' scope and any other account requires the 'user' scope
+*/
+func RequiredScopes(caller string, resource Resource, action Action, owner string) []string {
+	if resource == ResourceUser {
+		if owner == caller {
+			return []string{string(action) + ":profile"}
+		}
+
+		return []string{string(action) + ":user"}
+	}
+
+	scope := func(level string) string {
+		return string(action) + ":" + string(resource) + "." + level
+	}
+
+	switch {
+	case owner == SystemOwner && action == ActionWrite:
+		return []string{scope("wotc"), scope("admin")}
+	case owner == SystemOwner:
+		return []string{scope("wotc")}
+	case owner == caller && action == ActionWrite:
+		return []string{scope("user")}
+	case owner == caller:
+		return []string{scope("wotc")}
+	}
+
+	return []string{scope("admin")}
+}
+
+/*
+Evaluate Decide whether a caller holding the passed space separated scopes may perform the action against
+a resource owned by owner
+*/
+func Evaluate(scope string, caller string, resource Resource, action Action, owner string) Decision {
+	if owner == AllOwners && action == ActionWrite {
+		return Decision{Allowed: false, Reason: "An owner of '*' can only be used when reading " + string(resource) + "s"}
+	}
+
+	required := RequiredScopes(caller, resource, action, owner)
+	held := ExpandScope(scope)
+
+	allowed := true
+	for _, requiredScope := range required {
+		if !held[requiredScope] {
+			allowed = false
+		}
+	}
+
+	if allowed {
+		return Decision{Allowed: true, RequiredScope: strings.Join(required, " ")}
+	}
+
+	verb := "read"
+	if action == ActionWrite {
+		verb = "modify"
+	}
+
+	subject := "other users " + string(resource) + "s"
+	if owner == AllOwners {
+		subject = "every users " + string(resource) + "s"
+	} else if owner == SystemOwner {
+		subject = "system or pre-constructed " + string(resource) + "s"
+	} else if owner == caller {
+		subject = "your own " + string(resource) + "s"
+	}
+
+	if resource == ResourceUser {
+		subject = "other users accounts"
+		if owner == caller {
+			subject = "your own account"
+		}
+	}
+
+	return Decision{
+		Allowed:       false,
+		RequiredScope: strings.Join(required, " "),
+		Reason:        "Invalid permissions to " + verb + " " + subject,
+	}
+}
+
+/*
+Authorize Fetch validated claims and the caller's email from the gin context, and evaluate policy for
+the action against a resource owned by owner
+*/
+func Authorize(ctx *gin.Context, resource Resource, action Action, owner string) Decision {
+	token := ctx.Value("token").(*validator.ValidatedClaims)
+	claims := token.CustomClaims.(*CustomClaims)
+
+	return Evaluate(claims.Scope, ctx.GetString("userEmail"), resource, action, owner)
+}
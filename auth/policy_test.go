@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	const caller = "user@example.com"
+	const other = "other@example.com"
+
+	tests := []struct {
+		name     string
+		scope    string
+		resource Resource
+		action   Action
+		owner    string
+		allowed  bool
+		required string
+	}{
+		{"read system with wotc", "read:deck.wotc", ResourceDeck, ActionRead, SystemOwner, true, "read:deck.wotc"},
+		{"read system without scope", "read:card.wotc", ResourceDeck, ActionRead, SystemOwner, false, "read:deck.wotc"},
+		{"read system with admin", "read:deck.admin", ResourceDeck, ActionRead, SystemOwner, true, "read:deck.wotc"},
+		{"read self with wotc", "read:deck.wotc", ResourceDeck, ActionRead, caller, true, "read:deck.wotc"},
+		{"read self with user only", "read:deck.user", ResourceDeck, ActionRead, caller, false, "read:deck.wotc"},
+		{"read other with wotc", "read:deck.wotc", ResourceDeck, ActionRead, other, false, "read:deck.admin"},
+		{"read other with admin", "read:deck.admin", ResourceDeck, ActionRead, other, true, "read:deck.admin"},
+		{"read all with wotc", "read:card.wotc read:card.user", ResourceCard, ActionRead, AllOwners, false, "read:card.admin"},
+		{"read all with admin", "read:card.admin", ResourceCard, ActionRead, AllOwners, true, "read:card.admin"},
+
+		{"write system with wotc only", "write:set.wotc", ResourceSet, ActionWrite, SystemOwner, false, "write:set.wotc write:set.admin"},
+		{"write system with admin only", "write:set.admin", ResourceSet, ActionWrite, SystemOwner, false, "write:set.wotc write:set.admin"},
+		{"write system with wotc and admin", "write:set.wotc write:set.admin", ResourceSet, ActionWrite, SystemOwner, true, "write:set.wotc write:set.admin"},
+		{"write self with user", "write:deck.user", ResourceDeck, ActionWrite, caller, true, "write:deck.user"},
+		{"write self with admin", "write:deck.admin", ResourceDeck, ActionWrite, caller, true, "write:deck.user"},
+		{"write self with wotc", "write:deck.wotc", ResourceDeck, ActionWrite, caller, false, "write:deck.user"},
+		{"write other with user", "write:deck.user", ResourceDeck, ActionWrite, other, false, "write:deck.admin"},
+		{"write other with admin", "write:deck.admin", ResourceDeck, ActionWrite, other, true, "write:deck.admin"},
+		{"write all with admin", "write:deck.admin", ResourceDeck, ActionWrite, AllOwners, false, ""},
+
+		{"read own account with profile", "read:profile", ResourceUser, ActionRead, caller, true, "read:profile"},
+		{"read own account with user", "read:user", ResourceUser, ActionRead, caller, true, "read:profile"},
+		{"read other account with profile", "read:profile", ResourceUser, ActionRead, other, false, "read:user"},
+		{"write own account with profile", "write:profile", ResourceUser, ActionWrite, caller, true, "write:profile"},
+		{"write other account with user", "write:user", ResourceUser, ActionWrite, other, true, "write:user"},
+		{"write other account with profile", "write:profile", ResourceUser, ActionWrite, other, false, "write:user"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decision := Evaluate(test.scope, caller, test.resource, test.action, test.owner)
+			if decision.Allowed != test.allowed {
+				t.Errorf("Allowed = %v, want %v (reason: %q)", decision.Allowed, test.allowed, decision.Reason)
+			}
+
+			if decision.RequiredScope != test.required {
+				t.Errorf("RequiredScope = %q, want %q", decision.RequiredScope, test.required)
+			}
+
+			if !decision.Allowed && decision.Reason == "" {
+				t.Error("denied decision has no reason")
+			}
+		})
+	}
+}
+
+func TestExpandScope(t *testing.T) {
+	tests := []struct {
+		scope   string
+		implied []string
+		absent  []string
+	}{
+		{"read:deck.admin", []string{"read:deck.admin", "read:deck.user", "read:deck.wotc"}, []string{"read:card.wotc"}},
+		{"write:deck.admin", []string{"write:deck.admin", "write:deck.user"}, []string{"write:deck.wotc"}},
+		{"read:user write:user", []string{"read:profile", "write:profile"}, []string{"read:deck.wotc"}},
+		{"", nil, []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scope, func(t *testing.T) {
+			expanded := ExpandScope(test.scope)
+			for _, scope := range test.implied {
+				if !expanded[scope] {
+					t.Errorf("expected %q to imply %q", test.scope, scope)
+				}
+			}
+
+			for _, scope := range test.absent {
+				if expanded[scope] {
+					t.Errorf("expected %q not to imply %q", test.scope, scope)
+				}
+			}
+		})
+	}
+}
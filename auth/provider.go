@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/spf13/viper"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"net/url"
+)
+
+// ErrProviderNotSupported - Returned when an operation is not supported by the configured authentication provider
+var ErrProviderNotSupported = errors.New("auth: operation is not supported by the authentication provider")
+
+/*
+Provider - An abstraction of an authentication provider. A provider supplies the keys used for
+validating access tokens, and the account operations that the login, register, reset and user
+endpoints depend on. Providers that cannot support an account operation should return
+ErrProviderNotSupported
+*/
+type Provider interface {
+	// Algorithm - The algorithm that access tokens are signed with
+	Algorithm() validator.SignatureAlgorithm
+
+	// IssuerUrl - The URL of the issuer that access tokens must be issued by
+	IssuerUrl() *url.URL
+
+	// Audience - The audiences that access tokens must be issued for
+	Audience() []string
+
+	// KeyFunc - Returns the key set used for verifying the signature of access tokens
+	KeyFunc(ctx context.Context) (interface{}, error)
+
+	// GetEmailFromToken - Fetch the email address of the user that the access token was issued to
+	GetEmailFromToken(token string) (string, error)
+
+	// AuthenticateUser - Exchange an email address and password for an access token
+	AuthenticateUser(email string, password string) (interface{}, error)
+
+	// RegisterUser - Create a new user with the provider, returning the ID the provider assigned them
+	RegisterUser(username string, email string, password string) (string, error)
+
+	// ResetUserPassword - Send a password reset email to the user
+	ResetUserPassword(email string) error
+
+	// DeactivateUser - Block the user with the provider ID from authenticating
+	DeactivateUser(userId string) error
+}
+
+/*
+NewProvider - Create the authentication provider named in viper under the property 'auth.provider'.
+Supported values are 'auth0' (the default) and 'oidc'. If the property 'auth.mode' is set to 'local',
+then a LocalProvider is returned instead, regardless of the value of 'auth.provider'
+*/
+func NewProvider(server *server.Server) (Provider, error) {
+	if viper.GetString("auth.mode") == "local" {
+		return LocalProviderFromConfig()
+	}
+
+	switch viper.GetString("auth.provider") {
+	case "", "auth0":
+		return NewAuth0Provider(server), nil
+	case "oidc":
+		return OIDCProviderFromConfig()
+	}
+
+	return nil, fmt.Errorf("auth: unknown authentication provider '%s'", viper.GetString("auth.provider"))
+}
@@ -19,3 +19,14 @@ func ValidateScope(ctx *gin.Context, requiredScope string) bool {
 
 	return true
 }
+
+/*
+CallerScope Fetch validated claims from the gin context and return the space separated
+scopes held by the caller
+*/
+func CallerScope(ctx *gin.Context) string {
+	token := ctx.Value("token").(*validator.ValidatedClaims)
+
+	claims := token.CustomClaims.(*CustomClaims)
+	return claims.Scope
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/price"
+	"os"
+)
+
+// importPricesCmd - Imports an MTGJSON price file into the price collection
+var importPricesCmd = &cobra.Command{
+	Use:   "import-prices",
+	Short: "Import an MTGJSON AllPrices or AllPricesToday file",
+	Long: `Stream an MTGJSON AllPrices or AllPricesToday file from a local path into the price collection.
+Files compressed with gzip (.gz) or bzip2 (.bz2) are decompressed as they are read. A price is skipped if
+one is already stored for the same card, provider, finish, price type and day, so this can safely be run
+each day, or run again after an import fails partway through.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, _ := cmd.Flags().GetString("path")
+		if path == "" {
+			fmt.Println("A path to an MTGJSON price file is required to import prices")
+			os.Exit(1)
+		}
+
+		serv, err := server.FromConfig()
+		if err != nil {
+			fmt.Println("Failed to initialize server: ", err.Error())
+			os.Exit(1)
+		}
+
+		err = serv.Database().Connect()
+		if err != nil {
+			fmt.Println("Failed to connect to MongoDB: ", err.Error())
+			os.Exit(1)
+		}
+		defer serv.Database().Disconnect()
+
+		stats, err := price.ImportFile(serv, path)
+		if err != nil {
+			fmt.Println("Failed to import prices: ", err.Error())
+			serv.Database().Disconnect()
+			os.Exit(1)
+		}
+
+		fmt.Printf("Imported %d price points for %d cards (%d points were already stored and skipped)\n", stats.Points, stats.Cards, stats.Skipped)
+	},
+}
+
+/*
+init - Function automatically created by cobra. Used to register the import-prices command with the root
+command and declare its command line arguments. Should not be called directly
+*/
+func init() {
+	rootCmd.AddCommand(importPricesCmd)
+
+	importPricesCmd.Flags().String("path", "", "The path to an MTGJSON AllPrices or AllPricesToday file")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"mtgjson/auth"
+	"os"
+	"time"
+)
+
+// mintTokenCmd - Signs an access token using the local authentication provider
+var mintTokenCmd = &cobra.Command{
+	Use:   "mint-token",
+	Short: "Sign an access token for local development",
+	Long: `Sign an access token using the local authentication provider and print it to stdout. The API
+must be started with auth.mode set to local, using the same auth.local values, for the token to be accepted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		scope, _ := cmd.Flags().GetString("scope")
+		ttl, _ := cmd.Flags().GetInt("ttl")
+
+		if email == "" {
+			fmt.Println("An email address is required to mint a token")
+			os.Exit(1)
+		}
+
+		provider, err := auth.LocalProviderFromConfig()
+		if err != nil {
+			fmt.Println("Failed to initialize local authentication provider: ", err.Error())
+			os.Exit(1)
+		}
+
+		token, err := provider.MintToken(email, scope, time.Duration(ttl)*time.Second)
+		if err != nil {
+			fmt.Println("Failed to sign token: ", err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println(token)
+	},
+}
+
+/*
+init - Function automatically created by cobra. Used to register the mint-token command with the root
+command and declare its command line arguments. Should not be called directly
+*/
+func init() {
+	rootCmd.AddCommand(mintTokenCmd)
+
+	mintTokenCmd.Flags().String("email", "", "The email address of the user the token is issued to")
+	mintTokenCmd.Flags().String("scope", "", "A space seperated string of scopes the token should carry")
+	mintTokenCmd.Flags().Int("ttl", 3600, "The amount of seconds until the token expires (default is 3600)")
+}
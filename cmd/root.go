@@ -42,33 +42,8 @@ MTGJSON API not officially endorsed by MTGJSON`,
 			os.Exit(1)
 		}
 
-		// This is gross and needs to be reworked....
-		serv.RegisterEndpoint("POST", "/api/v1/login", "", false, api.LoginPOST)
-		serv.RegisterEndpoint("POST", "/api/v1/register", "", false, api.RegisterPOST)
-		serv.RegisterEndpoint("GET", "/api/v1/reset", "read:profile", true, api.CardGET)
-
-		serv.RegisterEndpoint("GET", "/api/v1/user", "read:user", true, api.UserGET)
-		serv.RegisterEndpoint("DELETE", "/api/v1/user", "write:user", true, api.UserDELETE)
-
-		serv.RegisterEndpoint("GET", "/api/v1/card", "read:card.wotc", true, api.CardGET)
-		serv.RegisterEndpoint("POST", "/api/v1/card", "write:card.user", true, api.CardPOST)
-		serv.RegisterEndpoint("DELETE", "/api/v1/card", "write:card.user", true, api.CardDELETE)
-
-		serv.RegisterEndpoint("GET", "/api/v1/deck", "read:deck.wotc", true, api.DeckGET)
-		serv.RegisterEndpoint("POST", "/api/v1/deck", "write:deck.user", true, api.DeckPOST)
-		serv.RegisterEndpoint("DELETE", "/api/v1/deck", "write:deck.user", true, api.DeckDELETE)
-
-		serv.RegisterEndpoint("GET", "/api/v1/deck/content", "read:deck.wotc", true, api.DeckContentGET)
-		serv.RegisterEndpoint("POST", "/api/v1/deck/content", "write:deck.user", true, api.DeckContentPOST)
-		serv.RegisterEndpoint("DELETE", "/api/v1/deck/content", "write:deck.user", true, api.DeckContentDELETE)
-
-		serv.RegisterEndpoint("GET", "/api/v1/set", "read:set.wotc", true, api.SetGET)
-		serv.RegisterEndpoint("POST", "/api/v1/set", "write:set.user", true, api.SetPOST)
-		serv.RegisterEndpoint("DELETE", "/api/v1/set", "write:set.user", true, api.SetDELETE)
-
-		serv.RegisterEndpoint("GET", "/api/v1/set/content", "read:set.wotc", true, api.SetContentGET)
-		serv.RegisterEndpoint("POST", "/api/v1/set/content", "write:set.user", true, api.SetContentPOST)
-		serv.RegisterEndpoint("DELETE", "/api/v1/set/content", "write:set.user", true, api.SetContentDELETE)
+		serv.RegisterVersion(serv.V1())
+		serv.RegisterVersion(serv.V2())
 
 		err = serv.Run(viper.GetInt("port"))
 		if err != nil {
@@ -103,6 +78,14 @@ func init() {
 	rootCmd.Flags().BoolP("verbose", "v", false, "Enable verbosity in logging (default is false)")
 	rootCmd.Flags().IntP("port", "p", 8080, "The port the API should be exposed on (default is 8080)")
 
+	/*
+		API CLI Flags - Any flags used for controlling how the API server is run
+	*/
+	rootCmd.Flags().String("api.v1_deprecation", "Sat, 17 Oct 2026 00:00:00 GMT", "An HTTP date returned in the Deprecation header of /api/v1 routes, describing when it was deprecated (default is Sat, 17 Oct 2026 00:00:00 GMT)")
+	rootCmd.Flags().String("api.v1_sunset", "", "An HTTP date returned in the Sunset header of deprecated /api/v1 routes")
+	rootCmd.Flags().Int64("api.max_page_size", 100, "The maximum amount of objects returned in a single page of an index (default is 100)")
+	rootCmd.Flags().Int("api.shutdown_timeout", 30, "The amount of seconds in-flight requests are given to complete during shutdown (default is 30)")
+
 	/*
 		MongoDB CLI Flags - Any flags used for identifying a MongoDB server
 	*/
@@ -121,6 +104,31 @@ func init() {
 	rootCmd.Flags().String("auth0.client_secret", "", "The Client Secret of your Auth0 application")
 	rootCmd.Flags().String("auth0.scope", "", "A space seperated string of Auth0 scopes that the API should recognize")
 
+	/*
+		Auth CLI Flags - Any flags used for selecting and configuring the authentication provider
+	*/
+	rootCmd.Flags().String("auth.provider", "auth0", "The authentication provider to use. Either auth0 or oidc (default is auth0)")
+	rootCmd.Flags().Int("auth.jwks_cache_ttl", 5, "The amount of minutes JWKS fetched from the provider are cached before being refreshed (default is 5)")
+	rootCmd.Flags().String("auth.email_claim", "", "The name of the token claim that the caller's email address is read from (ex. https://mtgjson.example/email)")
+	rootCmd.Flags().Int("auth.identity_cache_ttl", 0, "The amount of seconds an email fetched from the provider is cached for a token. Set to 0 to disable (default is 0)")
+	rootCmd.Flags().String("auth.oidc.issuer", "", "The issuer URL of your OpenID Connect provider")
+	rootCmd.Flags().String("auth.oidc.audience", "", "The audience that access tokens must be issued for")
+	rootCmd.Flags().String("auth.oidc.client_id", "", "The Client ID of your OpenID Connect client")
+	rootCmd.Flags().String("auth.oidc.client_secret", "", "The Client Secret of your OpenID Connect client")
+	rootCmd.Flags().String("auth.oidc.scope", "", "A space seperated string of scopes to request during login")
+	rootCmd.Flags().String("auth.oidc.algorithm", "", "The algorithm access tokens are signed with (ex. ES256). If empty, it is selected from the discovery document")
+
+	/*
+		Local Auth CLI Flags - Any flags used for signing tokens locally when auth.mode is set to local. These are
+		persistent so that the mint-token command signs tokens with the same values as the API
+	*/
+	rootCmd.Flags().String("auth.mode", "remote", "Set to local to have the API validate tokens it signs itself instead of using a provider (default is remote)")
+	rootCmd.PersistentFlags().String("auth.local.algorithm", "RS256", "The algorithm local tokens are signed with. Either RS256 or HS256 (default is RS256)")
+	rootCmd.PersistentFlags().String("auth.local.key_path", "~/.config/mtgjson-api/local.pem", "The path to the PEM encoded RSA private key used for RS256. Generated if it does not exist (default is ~/.config/mtgjson-api/local.pem)")
+	rootCmd.PersistentFlags().String("auth.local.secret", "", "The secret used for HS256. Must be at least 32 characters")
+	rootCmd.PersistentFlags().String("auth.local.issuer", "https://mtgjson-api.local/", "The issuer of local tokens (default is https://mtgjson-api.local/)")
+	rootCmd.PersistentFlags().String("auth.local.audience", "mtgjson-api", "The audience of local tokens (default is mtgjson-api)")
+
 	/*
 		Log CLI Flags - Any flags used for controlling slog logging features
 	*/
@@ -131,6 +139,10 @@ func init() {
 		of the command is used by default
 	*/
 	err := viper.BindPFlags(rootCmd.Flags())
+	if err == nil {
+		err = viper.BindPFlags(rootCmd.PersistentFlags())
+	}
+
 	if err != nil {
 		fmt.Println("Error binding Cobra flags to viper: ", err.Error())
 		fmt.Println("Viper config values may not work properly")
@@ -0,0 +1,106 @@
+package decklist
+
+import (
+	"errors"
+)
+
+const (
+	// BoardMain - The main board of a deck
+	BoardMain = "mainBoard"
+
+	// BoardSide - The sideboard of a deck. Companions are placed here, as that is where they are played from
+	BoardSide = "sideBoard"
+
+	// BoardCommander - The commander zone of a deck
+	BoardCommander = "commander"
+)
+
+const (
+	// FormatArena - The text format exported by MTG Arena, which declares each board under a header (ex. Sideboard)
+	FormatArena = "arena"
+
+	// FormatMTGO - The .dek XML format used by Magic: The Gathering Online
+	FormatMTGO = "mtgo"
+
+	// FormatText - A plain text list with one card per line (ex. 4 Lightning Bolt (M10) 146)
+	FormatText = "text"
+
+	// FormatCSV - A CSV file with one row per card. Decks can be exported to this format, but not imported from it
+	FormatCSV = "csv"
+
+	// FormatJSON - A JSON document listing the cards in each board. Decks can be exported to this format, but not
+	// imported from it
+	FormatJSON = "json"
+)
+
+var (
+	// ErrInvalidFormat - Returned when a decklist is requested in a format that is not supported
+	ErrInvalidFormat = errors.New("decklist: unsupported decklist format")
+
+	// ErrEmptyDecklist - Returned when a decklist does not contain any cards
+	ErrEmptyDecklist = errors.New("decklist: decklist does not contain any cards")
+)
+
+/*
+Entry - A single card read from a decklist
+*/
+type Entry struct {
+	// Line - The line of the decklist that the entry was read from, counted from one. For MTGO decklists,
+	// this is the position of the card element instead
+	Line int `json:"line"`
+
+	// Text - The raw text that the entry was read from
+	Text string `json:"text"`
+
+	// Board - The board that the card belongs to
+	Board string `json:"board"`
+
+	// Count - The amount of copies of the card
+	Count int64 `json:"count"`
+
+	// Name - The name of the card
+	Name string `json:"name"`
+
+	// SetCode - The code of the set the card was printed in. This may be empty
+	SetCode string `json:"setCode,omitempty"`
+
+	// Number - The collector number of the card. This may be empty
+	Number string `json:"number,omitempty"`
+
+	// MtgoId - The MTGO catalog ID of the card. This is only set for MTGO decklists
+	MtgoId string `json:"mtgoId,omitempty"`
+
+	// IsFoil - Set to true if the card was marked as foil
+	IsFoil bool `json:"isFoil"`
+}
+
+/*
+LineError - A line of a decklist that could not be imported, along with the reason why
+*/
+type LineError struct {
+	// Line - The line of the decklist, counted from one
+	Line int `json:"line"`
+
+	// Text - The raw text of the line
+	Text string `json:"text"`
+
+	// Reason - A human-readable explanation of why the line could not be imported
+	Reason string `json:"reason"`
+}
+
+/*
+Decklist - The cards read from a decklist, along with the lines that could not be parsed
+*/
+type Decklist struct {
+	// Name - The name of the deck, if the decklist declares one
+	Name string `json:"name,omitempty"`
+
+	// Format - The format that the decklist was parsed as
+	Format string `json:"format"`
+
+	// Entries - The cards read from the decklist, in the order they appear
+	Entries []*Entry `json:"entries"`
+
+	// Errors - The lines that could not be parsed
+	Errors []*LineError `json:"errors"`
+}
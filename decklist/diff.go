@@ -0,0 +1,176 @@
+package decklist
+
+import (
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+Change - A card whose quantity differs between two decks
+*/
+type Change struct {
+	// Name - The name of the card
+	Name string `json:"name"`
+
+	// From - The amount of copies in the first deck
+	From int64 `json:"from"`
+
+	// To - The amount of copies in the second deck
+	To int64 `json:"to"`
+
+	// Delta - The amount of copies added, or removed if negative
+	Delta int64 `json:"delta"`
+}
+
+/*
+BoardDiff - The differences between a single board of two decks
+*/
+type BoardDiff struct {
+	// Added - Cards that are only in the second deck
+	Added []*Change `json:"added"`
+
+	// Removed - Cards that are only in the first deck
+	Removed []*Change `json:"removed"`
+
+	// Changed - Cards that are in both decks, with a different amount of copies
+	Changed []*Change `json:"changed"`
+}
+
+/*
+Diff - The differences between the boards of two decks
+*/
+type Diff struct {
+	// Identical - Set to true if both decks contain the same cards
+	Identical bool `json:"identical"`
+
+	// Boards - The differences between each board, keyed by the board name
+	Boards map[string]*BoardDiff `json:"boards"`
+}
+
+/*
+countByName Return the amount of copies of each card in a board, keyed by card name. Different printings
+of a card are counted together
+*/
+func countByName(cards []*cardModel.CardDeck) map[string]int64 {
+	ret := make(map[string]int64)
+	for _, card := range cards {
+		ret[card.Name] += card.Count
+	}
+
+	return ret
+}
+
+/*
+diffBoard Return the differences between the same board of two decks, with the cards of each list sorted
+by name
+*/
+func diffBoard(from []*cardModel.CardDeck, to []*cardModel.CardDeck) *BoardDiff {
+	fromCounts := countByName(from)
+	toCounts := countByName(to)
+
+	names := make([]string, 0, len(fromCounts)+len(toCounts))
+	for name := range fromCounts {
+		names = append(names, name)
+	}
+
+	for name := range toCounts {
+		if _, ok := fromCounts[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	ret := &BoardDiff{Added: []*Change{}, Removed: []*Change{}, Changed: []*Change{}}
+	for _, name := range names {
+		change := &Change{Name: name, From: fromCounts[name], To: toCounts[name], Delta: toCounts[name] - fromCounts[name]}
+
+		switch {
+		case change.Delta == 0:
+			continue
+		case change.From == 0:
+			ret.Added = append(ret.Added, change)
+		case change.To == 0:
+			ret.Removed = append(ret.Removed, change)
+		default:
+			ret.Changed = append(ret.Changed, change)
+		}
+	}
+
+	return ret
+}
+
+/*
+Compare Return the cards added, removed and changed in each board when going from one deck to another.
+Cards are compared by name, so swapping one printing of a card for another is not a change
+*/
+func Compare(from *deckModel.DeckContents, to *deckModel.DeckContents) *Diff {
+	diff := &Diff{
+		Identical: true,
+		Boards: map[string]*BoardDiff{
+			BoardCommander: diffBoard(from.Commander, to.Commander),
+			BoardMain:      diffBoard(from.MainBoard, to.MainBoard),
+			BoardSide:      diffBoard(from.SideBoard, to.SideBoard),
+		},
+	}
+
+	for _, board := range diff.Boards {
+		if len(board.Added) != 0 || len(board.Removed) != 0 || len(board.Changed) != 0 {
+			diff.Identical = false
+		}
+	}
+
+	return diff
+}
+
+/*
+Text Render a diff as text, with one line per change grouped under a header for each board that changed
+(ex. +2 Counterspell). Cards that were added to a board are listed before cards that were removed from it
+*/
+func (diff *Diff) Text() string {
+	headers := []struct {
+		board  string
+		header string
+	}{
+		{BoardCommander, "Commander"},
+		{BoardMain, "Main Board"},
+		{BoardSide, "Sideboard"},
+	}
+
+	var buf strings.Builder
+	for _, header := range headers {
+		board := diff.Boards[header.board]
+
+		changes := slices.Concat(board.Added, board.Changed, board.Removed)
+		if len(changes) == 0 {
+			continue
+		}
+
+		slices.SortStableFunc(changes, func(a, b *Change) int {
+			if (a.Delta > 0) == (b.Delta > 0) {
+				return strings.Compare(a.Name, b.Name)
+			} else if a.Delta > 0 {
+				return -1
+			}
+			return 1
+		})
+
+		if buf.Len() != 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(header.header + "\n")
+
+		for _, change := range changes {
+			sign := "+"
+			if change.Delta < 0 {
+				sign = "-"
+			}
+
+			buf.WriteString(sign + strconv.FormatInt(max(change.Delta, -change.Delta), 10) + " " + change.Name + "\n")
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,240 @@
+package decklist
+
+import (
+	"bufio"
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+sections - Maps the section headers accepted in text decklists to the board that the cards following them
+belong to. An empty board means that the cards in the section are not part of the deck (ex. a maybeboard)
+*/
+var sections = map[string]string{
+	"deck":        BoardMain,
+	"main":        BoardMain,
+	"mainboard":   BoardMain,
+	"maindeck":    BoardMain,
+	"sideboard":   BoardSide,
+	"side":        BoardSide,
+	"companion":   BoardSide,
+	"commander":   BoardCommander,
+	"commanders":  BoardCommander,
+	"maybeboard":  "",
+	"considering": "",
+}
+
+/*
+entryPattern - Matches a single card in a text decklist. The count may be followed by an 'x', the set code
+may be wrapped in parentheses or brackets, and the line may end with a *F* foil marker
+(ex. 4x Lightning Bolt (M10) 146 *F*)
+*/
+var entryPattern = regexp.MustCompile(`^(?:(\d+)x?\s+)?(.+?)(?:\s+[(\[]([A-Za-z0-9]+)[)\]](?:\s+([A-Za-z0-9★†-]+))?)?(\s+\*F\*)?$`)
+
+/*
+DetectFormat Return the format of a decklist. MTGO decklists are detected by their XML declaration or root
+element, and text decklists containing an Arena section header are detected as Arena decklists
+*/
+func DetectFormat(input string) string {
+	trimmed := strings.TrimSpace(input)
+	if strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<Deck") {
+		return FormatMTGO
+	}
+
+	for _, line := range strings.Split(trimmed, "\n") {
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "deck", "sideboard", "commander", "companion", "about":
+			return FormatArena
+		}
+	}
+
+	return FormatText
+}
+
+/*
+Parse Parse a decklist in the passed format. If format is empty, then it is detected using DetectFormat.
+Lines that cannot be parsed are recorded in the Errors of the returned decklist rather than failing the
+parse. Returns ErrInvalidFormat if the format is not supported, or ErrEmptyDecklist if no cards were read
+*/
+func Parse(input string, format string) (*Decklist, error) {
+	if format == "" {
+		format = DetectFormat(input)
+	}
+
+	var list *Decklist
+	var err error
+
+	switch format {
+	case FormatArena, FormatText:
+		list = parseText(input)
+		list.Format = format
+	case FormatMTGO:
+		list, err = parseDek(input)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	if len(list.Entries) == 0 {
+		return list, ErrEmptyDecklist
+	}
+
+	return list, nil
+}
+
+/*
+parseText Parse an Arena or plain text decklist. Cards are placed in the main board until a section header
+is read. Lines prefixed with 'SB:' are placed in the sideboard, and if the decklist has no section headers,
+then a blank line separates the main board from the sideboard, as is done by MTGO text exports
+*/
+func parseText(input string) *Decklist {
+	list := &Decklist{Entries: []*Entry{}, Errors: []*LineError{}}
+
+	board := BoardMain
+	sawHeader := false
+	inAbout := false
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := scanner.Text()
+		line := strings.TrimSpace(text)
+
+		if line == "" {
+			if !sawHeader && len(list.Entries) != 0 {
+				board = BoardSide
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		header := strings.ToLower(strings.TrimSuffix(line, ":"))
+		if header == "about" {
+			sawHeader = true
+			inAbout = true
+			continue
+		}
+
+		if section, ok := sections[header]; ok {
+			sawHeader = true
+			inAbout = false
+			board = section
+			continue
+		}
+
+		if inAbout {
+			if name, ok := strings.CutPrefix(line, "Name "); ok {
+				list.Name = strings.TrimSpace(name)
+			}
+			continue
+		}
+
+		if board == "" {
+			continue
+		}
+
+		entryBoard := board
+		if rest, ok := strings.CutPrefix(line, "SB:"); ok {
+			entryBoard = BoardSide
+			line = strings.TrimSpace(rest)
+		}
+
+		match := entryPattern.FindStringSubmatch(line)
+		if match == nil {
+			list.Errors = append(list.Errors, &LineError{Line: lineNumber, Text: text, Reason: "line is not a card entry"})
+			continue
+		}
+
+		count := int64(1)
+		if match[1] != "" {
+			parsed, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil || parsed <= 0 {
+				list.Errors = append(list.Errors, &LineError{Line: lineNumber, Text: text, Reason: "card count must be a positive number"})
+				continue
+			}
+			count = parsed
+		}
+
+		list.Entries = append(list.Entries, &Entry{
+			Line:    lineNumber,
+			Text:    text,
+			Board:   entryBoard,
+			Count:   count,
+			Name:    strings.TrimSpace(match[2]),
+			SetCode: strings.ToUpper(match[3]),
+			Number:  match[4],
+			IsFoil:  match[5] != "",
+		})
+	}
+
+	return list
+}
+
+/*
+dekFile - The root element of an MTGO .dek file
+*/
+type dekFile struct {
+	XMLName              xml.Name  `xml:"Deck"`
+	NetDeckID            int       `xml:"NetDeckID"`
+	PreconstructedDeckID int       `xml:"PreconstructedDeckID"`
+	Cards                []dekCard `xml:"Cards"`
+}
+
+/*
+dekCard - A single card element of an MTGO .dek file
+*/
+type dekCard struct {
+	CatID     string `xml:"CatID,attr,omitempty"`
+	Quantity  string `xml:"Quantity,attr"`
+	Sideboard string `xml:"Sideboard,attr"`
+	Name      string `xml:"Name,attr"`
+}
+
+/*
+parseDek Parse an MTGO .dek decklist. The line of each entry is the position of its card element
+*/
+func parseDek(input string) (*Decklist, error) {
+	var file dekFile
+
+	err := xml.Unmarshal([]byte(input), &file)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &Decklist{Format: FormatMTGO, Entries: []*Entry{}, Errors: []*LineError{}}
+	for i, card := range file.Cards {
+		text := card.Quantity + " " + card.Name
+
+		count, err := strconv.ParseInt(card.Quantity, 10, 64)
+		if err != nil || count <= 0 {
+			list.Errors = append(list.Errors, &LineError{Line: i + 1, Text: text, Reason: "card quantity must be a positive number"})
+			continue
+		}
+
+		board := BoardMain
+		if strings.EqualFold(card.Sideboard, "true") {
+			board = BoardSide
+		}
+
+		list.Entries = append(list.Entries, &Entry{
+			Line:   i + 1,
+			Text:   text,
+			Board:  board,
+			Count:  count,
+			Name:   card.Name,
+			MtgoId: card.CatID,
+		})
+	}
+
+	return list, nil
+}
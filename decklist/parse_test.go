@@ -0,0 +1,230 @@
+package decklist
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+/*
+summarize Return a one line description of each entry in a decklist, so that parsed decklists can be compared
+*/
+func summarize(list *Decklist) []string {
+	summary := []string{}
+	for _, entry := range list.Entries {
+		line := fmt.Sprintf("%s %d %s", entry.Board, entry.Count, entry.Name)
+		if entry.SetCode != "" {
+			line += " (" + entry.SetCode + ")"
+		}
+		if entry.Number != "" {
+			line += " " + entry.Number
+		}
+		if entry.MtgoId != "" {
+			line += " #" + entry.MtgoId
+		}
+		if entry.IsFoil {
+			line += " *F*"
+		}
+		summary = append(summary, line)
+	}
+
+	return summary
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"xml declaration", "<?xml version=\"1.0\"?>\n<Deck></Deck>", FormatMTGO},
+		{"deck element", "  <Deck>\n</Deck>", FormatMTGO},
+		{"arena deck header", "Deck\n4 Lightning Bolt", FormatArena},
+		{"arena commander header", "Commander\n1 Atraxa, Praetors' Voice", FormatArena},
+		{"arena about header", "About\nName Burn\n\nDeck\n4 Lightning Bolt", FormatArena},
+		{"plain text", "4 Lightning Bolt\n\n2 Smash to Smithereens", FormatText},
+		{"mainboard header is not arena", "Mainboard\n4 Lightning Bolt", FormatText},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DetectFormat(test.input); got != test.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format string
+		want   []string
+		deck   string
+	}{
+		{
+			name:  "entry syntax",
+			input: "4 Lightning Bolt\n4x Lava Spike\nGoblin Guide\n2 Lightning Bolt (M10) 146\n1 Shock [m21] 159 *F*\n1 Fire // Ice",
+			want: []string{
+				"mainBoard 4 Lightning Bolt",
+				"mainBoard 4 Lava Spike",
+				"mainBoard 1 Goblin Guide",
+				"mainBoard 2 Lightning Bolt (M10) 146",
+				"mainBoard 1 Shock (M21) 159 *F*",
+				"mainBoard 1 Fire // Ice",
+			},
+		},
+		{
+			name:  "blank line starts the sideboard",
+			input: "4 Lightning Bolt\n4 Lava Spike\n\n2 Smash to Smithereens",
+			want: []string{
+				"mainBoard 4 Lightning Bolt",
+				"mainBoard 4 Lava Spike",
+				"sideBoard 2 Smash to Smithereens",
+			},
+		},
+		{
+			name:  "leading blank lines stay in the main board",
+			input: "\n\n4 Lightning Bolt\n\n2 Smash to Smithereens",
+			want: []string{
+				"mainBoard 4 Lightning Bolt",
+				"sideBoard 2 Smash to Smithereens",
+			},
+		},
+		{
+			name:  "blank lines are ignored once a header is read",
+			input: "Mainboard\n4 Lightning Bolt\n\n4 Lava Spike\nSideboard\n2 Smash to Smithereens",
+			want: []string{
+				"mainBoard 4 Lightning Bolt",
+				"mainBoard 4 Lava Spike",
+				"sideBoard 2 Smash to Smithereens",
+			},
+		},
+		{
+			name:  "sideboard prefix",
+			input: "4 Lightning Bolt\nSB: 2 Smash to Smithereens\n4 Lava Spike",
+			want: []string{
+				"mainBoard 4 Lightning Bolt",
+				"sideBoard 2 Smash to Smithereens",
+				"mainBoard 4 Lava Spike",
+			},
+		},
+		{
+			name:  "comments are skipped",
+			input: "// Burn\n# sideboard plan below\n4 Lightning Bolt",
+			want:  []string{"mainBoard 4 Lightning Bolt"},
+		},
+		{
+			name:  "arena sections",
+			input: "About\nName Mono Red Burn\n\nCommander\n1 Krenko, Mob Boss\n\nDeck\n4 Lightning Bolt (M10) 146\n\nSideboard\n2 Smash to Smithereens\n\nMaybeboard\n1 Shock",
+			want: []string{
+				"commander 1 Krenko, Mob Boss",
+				"mainBoard 4 Lightning Bolt (M10) 146",
+				"sideBoard 2 Smash to Smithereens",
+			},
+			deck: "Mono Red Burn",
+		},
+		{
+			name:  "companion is placed in the sideboard",
+			input: "Companion\n1 Lurrus of the Dream-Den\n\nDeck\n4 Lightning Bolt",
+			want: []string{
+				"sideBoard 1 Lurrus of the Dream-Den",
+				"mainBoard 4 Lightning Bolt",
+			},
+		},
+		{
+			name:   "forced text format",
+			input:  "Deck\n4 Lightning Bolt",
+			format: FormatText,
+			want:   []string{"mainBoard 4 Lightning Bolt"},
+		},
+		{
+			name: "mtgo",
+			input: `<?xml version="1.0" encoding="utf-8"?>
+<Deck xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+  <NetDeckID>0</NetDeckID>
+  <PreconstructedDeckID>0</PreconstructedDeckID>
+  <Cards CatID="12345" Quantity="4" Sideboard="false" Name="Lightning Bolt" />
+  <Cards CatID="23456" Quantity="2" Sideboard="true" Name="Smash to Smithereens" />
+</Deck>`,
+			want: []string{
+				"mainBoard 4 Lightning Bolt #12345",
+				"sideBoard 2 Smash to Smithereens #23456",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list, err := Parse(test.input, test.format)
+			if err != nil {
+				t.Fatalf("Parse() returned an error: %v", err)
+			}
+
+			if got := summarize(list); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse() entries =\n%q\nwant\n%q", got, test.want)
+			}
+
+			if list.Name != test.deck {
+				t.Errorf("Parse() name = %q, want %q", list.Name, test.deck)
+			}
+
+			if len(list.Errors) != 0 {
+				t.Errorf("Parse() returned unexpected line errors: %v", list.Errors[0])
+			}
+		})
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	list, err := Parse("4 Lightning Bolt\n0 Lava Spike\n2 Shock", FormatText)
+	if err != nil {
+		t.Fatalf("Parse() returned an error: %v", err)
+	}
+
+	if got, want := summarize(list), []string{"mainBoard 4 Lightning Bolt", "mainBoard 2 Shock"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() entries = %q, want %q", got, want)
+	}
+
+	if len(list.Errors) != 1 || list.Errors[0].Line != 2 || list.Errors[0].Text != "0 Lava Spike" {
+		t.Fatalf("expected a single error for line 2, got %v", list.Errors)
+	}
+
+	list, err = Parse(`<Deck><Cards Quantity="x" Name="Lightning Bolt" /><Cards Quantity="1" Name="Shock" /></Deck>`, "")
+	if err != nil {
+		t.Fatalf("Parse() returned an error: %v", err)
+	}
+
+	if len(list.Errors) != 1 || list.Errors[0].Line != 1 {
+		t.Errorf("expected a single error for the first card element, got %v", list.Errors)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format string
+		err    error
+	}{
+		{"unsupported format", "4 Lightning Bolt", FormatCSV, ErrInvalidFormat},
+		{"empty", "", "", ErrEmptyDecklist},
+		{"only comments", "// nothing here\n\n", FormatText, ErrEmptyDecklist},
+		{"only a maybeboard", "Maybeboard\n1 Shock", "", ErrEmptyDecklist},
+		{"empty mtgo deck", "<Deck></Deck>", "", ErrEmptyDecklist},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse(test.input, test.format); !errors.Is(err, test.err) {
+				t.Errorf("Parse() returned %v, want %v", err, test.err)
+			}
+		})
+	}
+
+	if _, err := Parse("<Deck><Cards", FormatMTGO); err == nil {
+		t.Error("expected malformed MTGO decklists to return an error")
+	}
+}
@@ -0,0 +1,294 @@
+package decklist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"strconv"
+	"strings"
+)
+
+/*
+Export - A deck rendered into a decklist format, ready to be served as a download
+*/
+type Export struct {
+	// ContentType - The MIME type of the rendered decklist
+	ContentType string
+
+	// Filename - The filename that the decklist should be downloaded as
+	Filename string
+
+	// Body - The rendered decklist
+	Body []byte
+}
+
+/*
+board - The cards in a single board of a deck
+*/
+type board struct {
+	name  string
+	cards []*cardModel.CardDeck
+}
+
+/*
+renderer - Renders the boards of a deck into a single decklist format
+*/
+type renderer struct {
+	contentType string
+	extension   string
+	render      func(deck *deckModel.Deck, boards []board) ([]byte, error)
+}
+
+/*
+renderers - The decklist formats that decks can be exported to
+*/
+var renderers = map[string]renderer{
+	FormatArena: {contentType: "text/plain; charset=utf-8", extension: "txt", render: renderArena},
+	FormatMTGO:  {contentType: "application/xml; charset=utf-8", extension: "dek", render: renderDek},
+	FormatText:  {contentType: "text/plain; charset=utf-8", extension: "txt", render: renderText},
+	FormatCSV:   {contentType: "text/csv; charset=utf-8", extension: "csv", render: renderCSV},
+	FormatJSON:  {contentType: "application/json; charset=utf-8", extension: "json", render: renderJSON},
+}
+
+/*
+Render Render the contents of a deck into the passed decklist format. Returns ErrInvalidFormat if the format
+is not supported
+*/
+func Render(deck *deckModel.Deck, contents *deckModel.DeckContents, format string) (*Export, error) {
+	r, ok := renderers[format]
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+
+	boards := []board{
+		{BoardCommander, contents.Commander},
+		{BoardMain, contents.MainBoard},
+		{BoardSide, contents.SideBoard},
+	}
+
+	body, err := r.render(deck, boards)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Export{ContentType: r.contentType, Filename: filename(deck.Code) + "." + r.extension, Body: body}, nil
+}
+
+/*
+filename Return a deck code with any characters that are not safe to use in a filename replaced
+*/
+func filename(code string) string {
+	if code == "" {
+		return "deck"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, code)
+}
+
+/*
+frontFace Return the name that a card is listed under by Arena and MTGO. Split cards are listed under both of
+their halves (ex. Fire // Ice), while every other card with multiple faces is listed under its front face
+*/
+func frontFace(card *cardModel.CardDeck) string {
+	if card.Layout == "split" || card.Layout == "aftermath" {
+		return card.Name
+	}
+
+	name, _, _ := strings.Cut(card.Name, " // ")
+	return name
+}
+
+/*
+cardUuid Return the UUID of a card in a deck, falling back to its mtgjsonV4Id if it does not have one
+*/
+func cardUuid(card *cardModel.CardDeck) string {
+	if card.Uuid == "" && card.Identifiers != nil {
+		return card.Identifiers.MtgjsonV4Id
+	}
+
+	return card.Uuid
+}
+
+/*
+textLine Render a single card as a line of a text decklist (ex. 4 Lightning Bolt (M10) 146 *F*)
+*/
+func textLine(card *cardModel.CardDeck) string {
+	line := strconv.FormatInt(card.Count, 10) + " " + frontFace(card)
+	if card.SetCode != "" {
+		line += " (" + card.SetCode + ")"
+		if card.Number != "" {
+			line += " " + card.Number
+		}
+	}
+
+	if card.IsFoil {
+		line += " *F*"
+	}
+
+	return line
+}
+
+/*
+renderArena Render a deck in the format imported by MTG Arena. Each board is declared under its own header,
+and the name of the deck is declared in the About section
+*/
+func renderArena(deck *deckModel.Deck, boards []board) ([]byte, error) {
+	headers := map[string]string{BoardCommander: "Commander", BoardMain: "Deck", BoardSide: "Sideboard"}
+
+	var buf strings.Builder
+	buf.WriteString("About\nName " + deck.Name + "\n")
+
+	for _, b := range boards {
+		if len(b.cards) == 0 {
+			continue
+		}
+
+		buf.WriteString("\n" + headers[b.name] + "\n")
+		for _, card := range b.cards {
+			buf.WriteString(textLine(card) + "\n")
+		}
+	}
+
+	return []byte(buf.String()), nil
+}
+
+/*
+renderText Render a deck as a plain text list. The sideboard follows the main board after a blank line, and
+if the deck has a commander, then each board is declared under its own header so that it can be told apart
+*/
+func renderText(deck *deckModel.Deck, boards []board) ([]byte, error) {
+	headers := map[string]string{BoardCommander: "Commander", BoardMain: "Mainboard", BoardSide: "Sideboard"}
+	useHeaders := len(boards[0].cards) != 0
+
+	var buf strings.Builder
+	for _, b := range boards {
+		if len(b.cards) == 0 {
+			continue
+		}
+
+		if buf.Len() != 0 {
+			buf.WriteString("\n")
+		}
+
+		if useHeaders {
+			buf.WriteString(headers[b.name] + "\n")
+		}
+
+		for _, card := range b.cards {
+			buf.WriteString(textLine(card) + "\n")
+		}
+	}
+
+	return []byte(buf.String()), nil
+}
+
+/*
+renderDek Render a deck as an MTGO .dek file. MTGO keeps the commander of a deck in its sideboard, so
+commanders are exported there
+*/
+func renderDek(deck *deckModel.Deck, boards []board) ([]byte, error) {
+	file := dekFile{Cards: []dekCard{}}
+	for _, b := range boards {
+		for _, card := range b.cards {
+			catId := ""
+			if card.Identifiers != nil {
+				catId = card.Identifiers.MtgoId
+			}
+
+			file.Cards = append(file.Cards, dekCard{
+				CatID:     catId,
+				Quantity:  strconv.FormatInt(card.Count, 10),
+				Sideboard: strconv.FormatBool(b.name != BoardMain),
+				Name:      frontFace(card),
+			})
+		}
+	}
+
+	body, err := xml.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+/*
+renderCSV Render a deck as a CSV file with a header row, followed by one row per card
+*/
+func renderCSV(deck *deckModel.Deck, boards []board) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := csv.NewWriter(&buf)
+	rows := [][]string{{"board", "count", "name", "setCode", "number", "isFoil", "uuid"}}
+	for _, b := range boards {
+		for _, card := range b.cards {
+			rows = append(rows, []string{
+				b.name,
+				strconv.FormatInt(card.Count, 10),
+				card.Name,
+				card.SetCode,
+				card.Number,
+				strconv.FormatBool(card.IsFoil),
+				cardUuid(card),
+			})
+		}
+	}
+
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return nil, fmt.Errorf("decklist: failed to write csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+/*
+exportCard - A single card of a deck exported to JSON
+*/
+type exportCard struct {
+	Count   int64  `json:"count"`
+	Name    string `json:"name"`
+	SetCode string `json:"setCode"`
+	Number  string `json:"number"`
+	IsFoil  bool   `json:"isFoil"`
+	Uuid    string `json:"uuid"`
+}
+
+/*
+renderJSON Render a deck as a JSON document containing the name, code and type of the deck, and the cards in
+each of its boards keyed by the board name
+*/
+func renderJSON(deck *deckModel.Deck, boards []board) ([]byte, error) {
+	exported := make(map[string][]exportCard, len(boards))
+	for _, b := range boards {
+		cards := make([]exportCard, 0, len(b.cards))
+		for _, card := range b.cards {
+			cards = append(cards, exportCard{
+				Count:   card.Count,
+				Name:    card.Name,
+				SetCode: card.SetCode,
+				Number:  card.Number,
+				IsFoil:  card.IsFoil,
+				Uuid:    cardUuid(card),
+			})
+		}
+
+		exported[b.name] = cards
+	}
+
+	return json.MarshalIndent(struct {
+		Code   string                  `json:"code"`
+		Name   string                  `json:"name"`
+		Type   string                  `json:"type"`
+		Boards map[string][]exportCard `json:"boards"`
+	}{deck.Code, deck.Name, deck.Type, exported}, "", "  ")
+}
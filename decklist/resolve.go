@@ -0,0 +1,148 @@
+package decklist
+
+import (
+	"errors"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"mtgjson/index"
+	"strings"
+)
+
+/*
+nameMatches Return true if the name of a card matches the name requested in a decklist, ignoring case.
+Decklists often only name the front face of double faced cards, so the front face of a card named
+'Front // Back' also matches
+*/
+func nameMatches(cardName string, requested string) bool {
+	cardName = strings.ToLower(cardName)
+	requested = strings.ToLower(strings.TrimSpace(requested))
+
+	return cardName == requested || strings.HasPrefix(cardName, requested+" // ")
+}
+
+/*
+resolver - Resolves the entries of a decklist to the cards owned by owner
+*/
+type resolver struct {
+	server *server.Server
+	names  *index.NameIndex
+	owner  string
+}
+
+/*
+resolve Resolve a single entry to the mtgjsonV4Id of a card. Entries are resolved by their MTGO ID, then by
+their set code and collector number, then by their name within their set, and finally by their name alone.
+If the entry cannot be resolved, then an empty ID is returned along with the reason why
+*/
+func (r *resolver) resolve(entry *Entry) (string, string, error) {
+	if entry.MtgoId != "" && entry.MtgoId != "0" {
+		card, err := index.GetCardByIdentifier(r.server, r.owner, "mtgoId", entry.MtgoId)
+		if err == nil && card.Identifiers != nil {
+			return card.Identifiers.MtgjsonV4Id, "", nil
+		} else if err != nil && !errors.Is(err, sdkErrors.ErrNoCard) {
+			return "", "", err
+		}
+	}
+
+	if entry.SetCode != "" && entry.Number != "" {
+		card, err := index.GetCardByPrinting(r.server, r.owner, entry.SetCode, entry.Number)
+		if err == nil && card.Identifiers != nil && nameMatches(card.Name, entry.Name) {
+			return card.Identifiers.MtgjsonV4Id, "", nil
+		} else if err != nil && !errors.Is(err, sdkErrors.ErrNoCard) {
+			return "", "", err
+		}
+	}
+
+	match, err := r.names.Fuzzy(r.owner, entry.Name)
+	if errors.Is(err, index.ErrNoCardName) {
+		return "", "no card named '" + entry.Name + "' was found", nil
+	} else if errors.Is(err, index.ErrAmbiguousCardName) {
+		return "", "the name '" + entry.Name + "' matches more than one card", nil
+	} else if err != nil {
+		return "", "", err
+	}
+
+	if match.Distance != 0 && !nameMatches(match.Name, entry.Name) {
+		return "", "no card named '" + entry.Name + "' was found. Did you mean '" + match.Name + "'?", nil
+	}
+
+	if entry.SetCode != "" {
+		card, err := index.GetCardByNameInSet(r.server, r.owner, match.Name, entry.SetCode)
+		if err == nil && card.Identifiers != nil {
+			return card.Identifiers.MtgjsonV4Id, "", nil
+		} else if err != nil && !errors.Is(err, sdkErrors.ErrNoCard) {
+			return "", "", err
+		}
+	}
+
+	return match.CardId, "", nil
+}
+
+/*
+Resolve Resolve each entry of a decklist to a card owned by owner, and build the contents of a deck from
+them. Entries for the same card in the same board with the same finish are combined. Entries that cannot be
+resolved are returned as LineErrors along with the lines that could not be parsed, sorted by line
+*/
+func Resolve(server *server.Server, names *index.NameIndex, owner string, list *Decklist) (*deckModel.DeckContentIds, []*LineError, error) {
+	r := &resolver{server: server, names: names, owner: owner}
+
+	contents := &deckModel.DeckContentIds{
+		MainBoard: []*deckModel.DeckContentEntry{},
+		SideBoard: []*deckModel.DeckContentEntry{},
+		Commander: []*deckModel.DeckContentEntry{},
+	}
+
+	type key struct {
+		board  string
+		uuid   string
+		isFoil bool
+	}
+	seen := make(map[key]*deckModel.DeckContentEntry)
+
+	lineErrors := append([]*LineError{}, list.Errors...)
+	for _, entry := range list.Entries {
+		uuid, reason, err := r.resolve(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if uuid == "" {
+			lineErrors = append(lineErrors, &LineError{Line: entry.Line, Text: entry.Text, Reason: reason})
+			continue
+		}
+
+		k := key{board: entry.Board, uuid: uuid, isFoil: entry.IsFoil}
+		if existing, ok := seen[k]; ok {
+			existing.Count += entry.Count
+			continue
+		}
+
+		contentEntry := &deckModel.DeckContentEntry{Uuid: uuid, Count: entry.Count, IsFoil: entry.IsFoil}
+		seen[k] = contentEntry
+
+		switch entry.Board {
+		case BoardSide:
+			contents.SideBoard = append(contents.SideBoard, contentEntry)
+		case BoardCommander:
+			contents.Commander = append(contents.Commander, contentEntry)
+		default:
+			contents.MainBoard = append(contents.MainBoard, contentEntry)
+		}
+	}
+
+	sortLineErrors(lineErrors)
+
+	return contents, lineErrors, nil
+}
+
+/*
+sortLineErrors Sort line errors by the line they occurred on
+*/
+func sortLineErrors(lineErrors []*LineError) {
+	for i := 1; i < len(lineErrors); i++ {
+		for j := i; j > 0 && lineErrors[j].Line < lineErrors[j-1].Line; j-- {
+			lineErrors[j], lineErrors[j-1] = lineErrors[j-1], lineErrors[j]
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package deckmeta
+
+import (
+	"context"
+	"errors"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"mtgjson/index"
+)
+
+// FormatField - The field of a deck document that its declared format is stored under
+const FormatField = "mtgjsonApiFormat"
+
+/*
+SetFormat Declare the format of the deck owned by owner with the passed code. The contents of the deck are
+validated against this format each time they are written. Passing an empty format removes the declared
+format. Returns sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func SetFormat(server *server.Server, code string, owner string, format string) error {
+	update := bson.M{"$set": bson.M{FormatField: format}}
+	if format == "" {
+		update = bson.M{"$unset": bson.M{FormatField: ""}}
+	}
+
+	result, err := server.Database().Database().Collection(index.DeckCollection).UpdateOne(
+		context.Background(),
+		deckFilter(code, owner),
+		update,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return sdkErrors.ErrNoDeck
+	}
+
+	return nil
+}
+
+/*
+GetFormat Fetch the declared format of the deck owned by owner with the passed code. An empty string is
+returned if the deck was not declared under a format. Returns sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func GetFormat(server *server.Server, code string, owner string) (string, error) {
+	var result struct {
+		Format string `bson:"mtgjsonApiFormat"`
+	}
+
+	err := server.Database().Database().Collection(index.DeckCollection).FindOne(
+		context.Background(),
+		deckFilter(code, owner),
+		options.FindOne().SetProjection(bson.M{FormatField: 1}),
+	).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", sdkErrors.ErrNoDeck
+	} else if err != nil {
+		return "", err
+	}
+
+	return result.Format, nil
+}
@@ -0,0 +1,85 @@
+package deckmeta
+
+import (
+	"context"
+	"errors"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"mtgjson/index"
+	"time"
+)
+
+// ProvenanceField - The field of a deck document that its provenance is stored under
+const ProvenanceField = "mtgjsonApiProvenance"
+
+/*
+Provenance - Records the deck that a deck was cloned from
+*/
+type Provenance struct {
+	// DeckCode - The code of the source deck
+	DeckCode string `json:"deckCode" bson:"deckCode"`
+
+	// Owner - The owner of the source deck
+	Owner string `json:"owner" bson:"owner"`
+
+	// Name - The name of the source deck at the time it was cloned
+	Name string `json:"name" bson:"name"`
+
+	// ClonedAt - The time that the deck was cloned
+	ClonedAt time.Time `json:"clonedAt" bson:"clonedAt"`
+}
+
+/*
+deckFilter Return a MongoDB filter matching the deck owned by owner with the passed code
+*/
+func deckFilter(code string, owner string) bson.M {
+	return bson.M{"code": code, index.OwnerField: owner}
+}
+
+/*
+SetProvenance Record the provenance of the deck owned by owner with the passed code. The provenance is stored
+alongside the deck, so it is removed when the deck is deleted. Returns sdkErrors.ErrNoDeck if the deck does
+not exist
+*/
+func SetProvenance(server *server.Server, code string, owner string, provenance *Provenance) error {
+	result, err := server.Database().Database().Collection(index.DeckCollection).UpdateOne(
+		context.Background(),
+		deckFilter(code, owner),
+		bson.M{"$set": bson.M{ProvenanceField: provenance}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return sdkErrors.ErrNoDeck
+	}
+
+	return nil
+}
+
+/*
+GetProvenance Fetch the provenance of the deck owned by owner with the passed code. A nil provenance is
+returned if the deck was not cloned from another deck. Returns sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func GetProvenance(server *server.Server, code string, owner string) (*Provenance, error) {
+	var result struct {
+		Provenance *Provenance `bson:"mtgjsonApiProvenance"`
+	}
+
+	err := server.Database().Database().Collection(index.DeckCollection).FindOne(
+		context.Background(),
+		deckFilter(code, owner),
+		options.FindOne().SetProjection(bson.M{ProvenanceField: 1}),
+	).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, sdkErrors.ErrNoDeck
+	} else if err != nil {
+		return nil, err
+	}
+
+	return result.Provenance, nil
+}
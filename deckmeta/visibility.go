@@ -0,0 +1,210 @@
+package deckmeta
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"mtgjson/index"
+	"time"
+)
+
+// SharingField - The field of a deck document that its visibility and share token are stored under
+const SharingField = "mtgjsonApiSharing"
+
+const (
+	// VisibilityPrivate - The deck can only be read by its owner, and by admins. This is the default
+	VisibilityPrivate = "private"
+
+	// VisibilityUnlisted - The deck can also be read by anyone holding its share token
+	VisibilityUnlisted = "unlisted"
+
+	// VisibilityPublic - The deck can be read by any authenticated caller
+	VisibilityPublic = "public"
+)
+
+// ErrInvalidVisibility - Returned when a deck is given a visibility that is not supported
+var ErrInvalidVisibility = errors.New("deckmeta: visibility must be one of private, unlisted or public")
+
+/*
+Sharing - The visibility of a deck, along with its share token
+*/
+type Sharing struct {
+	// Visibility - Who the deck can be read by. One of private, unlisted or public
+	Visibility string `json:"visibility" bson:"visibility"`
+
+	// TokenHash - The hex encoded SHA-256 hash of the share token. Only the hash is stored, so the raw token
+	// is only ever returned once, when it is created
+	TokenHash string `json:"-" bson:"tokenHash,omitempty"`
+
+	// HasToken - Set to true if the deck has a share token
+	HasToken bool `json:"hasToken" bson:"-"`
+
+	// TokenCreationDate - The time that the current share token was created
+	TokenCreationDate *time.Time `json:"tokenCreationDate,omitempty" bson:"tokenCreationDate,omitempty"`
+}
+
+/*
+hashToken Return the hex encoded SHA-256 hash of a share token
+*/
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+Allows Return true if a caller holding the passed share token may read the deck. Public decks can be read
+by anyone, and unlisted decks can be read by anyone holding the current share token
+*/
+func (sharing *Sharing) Allows(token string) bool {
+	switch sharing.Visibility {
+	case VisibilityPublic:
+		return true
+	case VisibilityUnlisted:
+		if token == "" || sharing.TokenHash == "" {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(sharing.TokenHash)) == 1
+	}
+
+	return false
+}
+
+/*
+GetSharing Fetch the visibility of the deck owned by owner with the passed code. Decks that have never been
+shared are private. Returns sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func GetSharing(server *server.Server, code string, owner string) (*Sharing, error) {
+	var result struct {
+		Sharing *Sharing `bson:"mtgjsonApiSharing"`
+	}
+
+	err := server.Database().Database().Collection(index.DeckCollection).FindOne(
+		context.Background(),
+		deckFilter(code, owner),
+		options.FindOne().SetProjection(bson.M{SharingField: 1}),
+	).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, sdkErrors.ErrNoDeck
+	} else if err != nil {
+		return nil, err
+	}
+
+	sharing := result.Sharing
+	if sharing == nil {
+		sharing = &Sharing{}
+	}
+
+	if sharing.Visibility == "" {
+		sharing.Visibility = VisibilityPrivate
+	}
+	sharing.HasToken = sharing.TokenHash != ""
+
+	return sharing, nil
+}
+
+/*
+updateSharing Apply an update to the sharing fields of the deck owned by owner with the passed code. Returns
+sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func updateSharing(server *server.Server, code string, owner string, update bson.M) error {
+	result, err := server.Database().Database().Collection(index.DeckCollection).UpdateOne(
+		context.Background(),
+		deckFilter(code, owner),
+		update,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return sdkErrors.ErrNoDeck
+	}
+
+	return nil
+}
+
+/*
+SetVisibility Change the visibility of the deck owned by owner with the passed code. The share token of the
+deck is kept, so that it works again if the deck is made unlisted later. Returns ErrInvalidVisibility if the
+visibility is not supported, or sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func SetVisibility(server *server.Server, code string, owner string, visibility string) error {
+	switch visibility {
+	case VisibilityPrivate, VisibilityUnlisted, VisibilityPublic:
+	default:
+		return ErrInvalidVisibility
+	}
+
+	return updateSharing(server, code, owner, bson.M{"$set": bson.M{SharingField + ".visibility": visibility}})
+}
+
+/*
+RotateShareToken Create a new share token for the deck owned by owner with the passed code, replacing any
+previous token. Private decks are made unlisted so that the new token can be used. The raw token is returned,
+and cannot be fetched again. Returns sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func RotateShareToken(server *server.Server, code string, owner string) (string, *Sharing, error) {
+	sharing, err := GetSharing(server, code, owner)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := make([]byte, 24)
+
+	_, err = rand.Read(buf)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	now := time.Now().UTC()
+
+	if sharing.Visibility == VisibilityPrivate {
+		sharing.Visibility = VisibilityUnlisted
+	}
+	sharing.TokenHash = hashToken(token)
+	sharing.HasToken = true
+	sharing.TokenCreationDate = &now
+
+	err = updateSharing(server, code, owner, bson.M{"$set": bson.M{SharingField: sharing}})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, sharing, nil
+}
+
+/*
+RevokeShareToken Remove the share token of the deck owned by owner with the passed code, so that it can no
+longer be read with it. Unlisted decks are made private, as they can no longer be shared. Returns
+sdkErrors.ErrNoDeck if the deck does not exist
+*/
+func RevokeShareToken(server *server.Server, code string, owner string) (*Sharing, error) {
+	sharing, err := GetSharing(server, code, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if sharing.Visibility == VisibilityUnlisted {
+		sharing.Visibility = VisibilityPrivate
+	}
+	sharing.TokenHash = ""
+	sharing.HasToken = false
+	sharing.TokenCreationDate = nil
+
+	err = updateSharing(server, code, owner, bson.M{"$set": bson.M{SharingField: sharing}})
+	if err != nil {
+		return nil, err
+	}
+
+	return sharing, nil
+}
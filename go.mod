@@ -11,6 +11,8 @@ require (
 	github.com/spf13/viper v1.19.0
 	github.com/stevezaluk/mtgjson-models v1.3.9
 	github.com/stevezaluk/mtgjson-sdk v1.4.9
+	go.mongodb.org/mongo-driver v1.17.1
+	gopkg.in/go-jose/go-jose.v2 v2.6.3
 )
 
 require (
@@ -66,7 +68,6 @@ require (
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
 	go.devnw.com/structs v1.0.0 // indirect
-	go.mongodb.org/mongo-driver v1.17.1 // indirect
 	go.opentelemetry.io/otel v1.29.0 // indirect
 	go.opentelemetry.io/otel/trace v1.29.0 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
@@ -80,7 +81,6 @@ require (
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 	google.golang.org/protobuf v1.35.2 // indirect
-	gopkg.in/go-jose/go-jose.v2 v2.6.3 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
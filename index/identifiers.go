@@ -0,0 +1,183 @@
+package index
+
+import (
+	"context"
+	"errors"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	sdkErrors "github.com/stevezaluk/mtgjson-models/errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidIdType - Returned when a card is looked up by an identifier type that is not supported
+var ErrInvalidIdType = errors.New("index: unsupported card identifier type")
+
+/*
+identifierFields - Maps each identifier type that a card can be looked up by to the field it is stored under
+*/
+var identifierFields = map[string]string{
+	"mtgjsonV4Id":        "identifiers.mtgjsonV4Id",
+	"scryfallId":         "identifiers.scryfallId",
+	"multiverseId":       "identifiers.multiverseId",
+	"mtgoId":             "identifiers.mtgoId",
+	"mtgArenaId":         "identifiers.mtgArenaId",
+	"tcgplayerProductId": "identifiers.tcgplayerProductId",
+	"cardKingdomId":      "identifiers.cardKingdomId",
+}
+
+/*
+IdTypes Return the identifier types that cards can be looked up by, in alphabetical order
+*/
+func IdTypes() []string {
+	ret := make([]string, 0, len(identifierFields))
+	for idType := range identifierFields {
+		ret = append(ret, idType)
+	}
+	slices.Sort(ret)
+
+	return ret
+}
+
+/*
+Identifier - A single identifier of a card, along with the type of the identifier
+*/
+type Identifier struct {
+	// IdType - The type of the identifier (ex. scryfallId or tcgplayerProductId)
+	IdType string `json:"idType" bson:"idType"`
+
+	// Id - The value of the identifier
+	Id string `json:"id" bson:"id"`
+}
+
+/*
+Resolution - The result of resolving an Identifier to the mtgjsonV4Id of the card it belongs to
+*/
+type Resolution struct {
+	Identifier
+
+	// MtgjsonV4Id - The mtgjsonV4Id of the card. This is empty if the identifier could not be resolved
+	MtgjsonV4Id string `json:"mtgjsonV4Id,omitempty"`
+
+	// Found - Set to true if the identifier was resolved
+	Found bool `json:"found"`
+}
+
+/*
+GetCardByIdentifier Fetch a card owned by owner using any of its identifiers. Returns ErrInvalidIdType if
+the identifier type is not supported, or sdkErrors.ErrNoCard if no card has the identifier
+*/
+func GetCardByIdentifier(server *server.Server, owner string, idType string, id string) (*cardModel.CardSet, error) {
+	field, ok := identifierFields[idType]
+	if !ok {
+		return nil, ErrInvalidIdType
+	}
+
+	return findCard(server, owner, bson.M{field: id})
+}
+
+/*
+GetCardByPrinting Fetch the card owned by owner with the passed collector number in the set with the passed
+code. Returns sdkErrors.ErrNoCard if the card does not exist
+*/
+func GetCardByPrinting(server *server.Server, owner string, setCode string, number string) (*cardModel.CardSet, error) {
+	return findCard(server, owner, bson.M{"setCode": strings.ToUpper(setCode), "number": number})
+}
+
+/*
+GetCardByNameInSet Fetch a card owned by owner with exactly the passed name, in the set with the passed code.
+Returns sdkErrors.ErrNoCard if the card does not exist
+*/
+func GetCardByNameInSet(server *server.Server, owner string, name string, setCode string) (*cardModel.CardSet, error) {
+	return findCard(server, owner, bson.M{"name": name, "setCode": strings.ToUpper(setCode)})
+}
+
+/*
+findCard Fetch a single card owned by owner matching filter. Returns sdkErrors.ErrNoCard if no card matches
+*/
+func findCard(server *server.Server, owner string, filter bson.M) (*cardModel.CardSet, error) {
+	var result cardModel.CardSet
+
+	err := server.Database().Database().Collection(CardCollection).FindOne(
+		context.Background(),
+		bson.M{"$and": bson.A{OwnerFilter(owner), filter}},
+	).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, sdkErrors.ErrNoCard
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+/*
+ResolveIdentifiers Resolve each identifier to the mtgjsonV4Id of the card owned by owner that it belongs to.
+Identifiers of the same type are resolved with a single query, and the resolutions are returned in the same
+order as the identifiers. Returns ErrInvalidIdType if any identifier type is not supported
+*/
+func ResolveIdentifiers(server *server.Server, owner string, identifiers []Identifier) ([]Resolution, error) {
+	byType := make(map[string][]string)
+	for _, identifier := range identifiers {
+		if _, ok := identifierFields[identifier.IdType]; !ok {
+			return nil, ErrInvalidIdType
+		}
+
+		byType[identifier.IdType] = append(byType[identifier.IdType], identifier.Id)
+	}
+
+	ctx := context.Background()
+	coll := server.Database().Database().Collection(CardCollection)
+
+	resolved := make(map[Identifier]string)
+	for idType, ids := range byType {
+		field := identifierFields[idType]
+		key := strings.TrimPrefix(field, "identifiers.")
+
+		cursor, err := coll.Find(
+			ctx,
+			bson.M{"$and": bson.A{OwnerFilter(owner), bson.M{field: bson.M{"$in": ids}}}},
+			options.Find().SetProjection(bson.M{"identifiers": 1}),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for cursor.Next(ctx) {
+			var result struct {
+				Identifiers map[string]interface{} `bson:"identifiers"`
+			}
+
+			err = cursor.Decode(&result)
+			if err != nil {
+				cursor.Close(ctx)
+				return nil, err
+			}
+
+			id, _ := result.Identifiers[key].(string)
+			v4Id, _ := result.Identifiers["mtgjsonV4Id"].(string)
+
+			identifier := Identifier{IdType: idType, Id: id}
+			if _, ok := resolved[identifier]; !ok {
+				resolved[identifier] = v4Id
+			}
+		}
+
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]Resolution, len(identifiers))
+	for i, identifier := range identifiers {
+		v4Id, ok := resolved[identifier]
+		ret[i] = Resolution{Identifier: identifier, MtgjsonV4Id: v4Id, Found: ok}
+	}
+
+	return ret, nil
+}
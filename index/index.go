@@ -0,0 +1,113 @@
+package index
+
+import (
+	"context"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	setModel "github.com/stevezaluk/mtgjson-models/set"
+	userModel "github.com/stevezaluk/mtgjson-models/user"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"mtgjson/auth"
+)
+
+const (
+	// CardCollection - The MongoDB collection that cards are stored in
+	CardCollection = "card"
+
+	// DeckCollection - The MongoDB collection that decks are stored in
+	DeckCollection = "deck"
+
+	// SetCollection - The MongoDB collection that sets are stored in
+	SetCollection = "set"
+
+	// UserCollection - The MongoDB collection that users are stored in
+	UserCollection = "user"
+
+	// OwnerField - The field that the owner of an object is stored under
+	OwnerField = "mtgjsonApiMeta.owner"
+)
+
+/*
+OwnerFilter Return a MongoDB filter matching objects owned by owner. If owner is auth.AllOwners, then the
+filter matches every object
+*/
+func OwnerFilter(owner string) bson.M {
+	if owner == auth.AllOwners {
+		return bson.M{}
+	}
+
+	return bson.M{OwnerField: owner}
+}
+
+// defaultSort - Sorts objects by their MongoDB ID so that pages are stable
+var defaultSort = bson.D{{Key: "_id", Value: 1}}
+
+/*
+find Fetch a single page of the objects in collection matching filter, decoding each into T. Objects
+are ordered by the sort parameter, which should end with a unique field so that pages are stable. The
+total amount of objects matching filter is returned along with the page. An empty slice is returned if the
+page is empty
+*/
+func find[T any](server *server.Server, collection string, filter bson.M, sort bson.D, page Page) ([]*T, int64, error) {
+	ctx := context.Background()
+	coll := server.Database().Database().Collection(collection)
+
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(page.Offset).
+		SetLimit(page.Limit)
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*T{}
+
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+/*
+Cards Fetch a page of the cards owned by owner, along with the total amount of cards they own. An empty
+slice is returned if the page is empty, such as when the cursor is past the last page
+*/
+func Cards(server *server.Server, owner string, page Page) ([]*cardModel.CardSet, int64, error) {
+	return find[cardModel.CardSet](server, CardCollection, OwnerFilter(owner), defaultSort, page)
+}
+
+/*
+Decks Fetch a page of the decks owned by owner, along with the total amount of decks they own. An empty
+slice is returned if the page is empty, such as when the cursor is past the last page
+*/
+func Decks(server *server.Server, owner string, page Page) ([]*deckModel.Deck, int64, error) {
+	return find[deckModel.Deck](server, DeckCollection, OwnerFilter(owner), defaultSort, page)
+}
+
+/*
+Sets Fetch a page of the sets owned by owner, along with the total amount of sets they own. An empty
+slice is returned if the page is empty, such as when the cursor is past the last page
+*/
+func Sets(server *server.Server, owner string, page Page) ([]*setModel.Set, int64, error) {
+	return find[setModel.Set](server, SetCollection, OwnerFilter(owner), defaultSort, page)
+}
+
+/*
+Users Fetch a page of the users registered with the API, along with the total amount of users. An empty
+slice is returned if the page is empty, such as when the cursor is past the last page
+*/
+func Users(server *server.Server, page Page) ([]*userModel.User, int64, error) {
+	return find[userModel.User](server, UserCollection, bson.M{}, defaultSort, page)
+}
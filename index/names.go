@@ -0,0 +1,411 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"mtgjson/auth"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	// ErrNoCardName - Returned when no card name is close enough to the name passed to a fuzzy lookup
+	ErrNoCardName = errors.New("index: failed to find a card matching the name")
+
+	// ErrAmbiguousCardName - Returned when more than one card name is equally close to the name passed to a fuzzy lookup
+	ErrAmbiguousCardName = errors.New("index: name matches more than one card")
+)
+
+/*
+nameEntry - A single card name in the name index, along with the IDs of every card sharing that name
+*/
+type nameEntry struct {
+	// name - The name of the card as it is stored
+	name string
+
+	// key - The normalized name of the card, used for comparisons
+	key string
+
+	// cardIds - The mtgjsonV4Id of each card with this name
+	cardIds []string
+}
+
+/*
+ownerNames - The card names owned by a single owner. Keys is kept sorted so that prefix lookups can use
+a binary search
+*/
+type ownerNames struct {
+	owner   string
+	entries map[string]*nameEntry
+	keys    []string
+}
+
+/*
+NameMatch - The result of a fuzzy name lookup
+*/
+type NameMatch struct {
+	// Name - The canonical name of the matched card
+	Name string
+
+	// CardId - The mtgjsonV4Id of a card with the matched name
+	CardId string
+
+	// Owner - The owner of the card
+	Owner string
+
+	// Distance - The edit distance between the requested name and the matched name
+	Distance int
+}
+
+/*
+NameIndex - An in-memory index of card names, grouped by owner, that backs name autocompletion and
+fuzzy name lookups. It is safe for concurrent use
+*/
+type NameIndex struct {
+	mutex  sync.RWMutex
+	owners map[string]*ownerNames
+}
+
+/*
+NewNameIndex - A constructor for an empty NameIndex. Call Load to fill it from the card collection
+*/
+func NewNameIndex() *NameIndex {
+	return &NameIndex{owners: make(map[string]*ownerNames)}
+}
+
+/*
+accentFolder - Replaces the accented characters that appear in card names (ex. Lim-Dûl's Vault) with
+their unaccented forms, so that they can be typed without them
+*/
+var accentFolder = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ä", "a", "ã", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c", "æ", "ae",
+)
+
+/*
+normalizeName Return the form of a card name used for comparisons. Names are lower cased, accents and
+punctuation are dropped, and runs of whitespace are collapsed into a single space
+*/
+func normalizeName(name string) string {
+	var ret strings.Builder
+
+	space := false
+	for _, r := range accentFolder.Replace(strings.ToLower(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && ret.Len() != 0 {
+				ret.WriteRune(' ')
+			}
+			space = false
+			ret.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '/':
+			space = true
+		}
+	}
+
+	return ret.String()
+}
+
+/*
+Load Replace the contents of the index with the name of every card in the card collection
+*/
+func (idx *NameIndex) Load(server *server.Server) error {
+	ctx := context.Background()
+
+	cursor, err := server.Database().Database().Collection(CardCollection).Find(
+		ctx,
+		bson.M{},
+		options.Find().SetProjection(bson.M{"name": 1, "identifiers.mtgjsonV4Id": 1, OwnerField: 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	owners := make(map[string]*ownerNames)
+	for cursor.Next(ctx) {
+		var result struct {
+			Name        string `bson:"name"`
+			Identifiers struct {
+				MtgjsonV4Id string `bson:"mtgjsonV4Id"`
+			} `bson:"identifiers"`
+			MtgjsonApiMeta struct {
+				Owner string `bson:"owner"`
+			} `bson:"mtgjsonApiMeta"`
+		}
+
+		err = cursor.Decode(&result)
+		if err != nil {
+			return err
+		}
+
+		add(owners, result.MtgjsonApiMeta.Owner, result.Name, result.Identifiers.MtgjsonV4Id)
+	}
+
+	err = cursor.Err()
+	if err != nil {
+		return err
+	}
+
+	idx.mutex.Lock()
+	idx.owners = owners
+	idx.mutex.Unlock()
+
+	return nil
+}
+
+/*
+add Add a card name to owners without taking a lock
+*/
+func add(owners map[string]*ownerNames, owner string, name string, cardId string) {
+	key := normalizeName(name)
+	if key == "" {
+		return
+	}
+
+	names, ok := owners[owner]
+	if !ok {
+		names = &ownerNames{owner: owner, entries: make(map[string]*nameEntry)}
+		owners[owner] = names
+	}
+
+	entry, ok := names.entries[key]
+	if !ok {
+		entry = &nameEntry{name: name, key: key}
+		names.entries[key] = entry
+
+		i, _ := slices.BinarySearch(names.keys, key)
+		names.keys = slices.Insert(names.keys, i, key)
+	}
+
+	if !slices.Contains(entry.cardIds, cardId) {
+		entry.cardIds = append(entry.cardIds, cardId)
+	}
+}
+
+/*
+Add Record that a card with the passed name and ID is owned by owner. This should be called whenever a
+card is created
+*/
+func (idx *NameIndex) Add(owner string, name string, cardId string) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	add(idx.owners, owner, name, cardId)
+}
+
+/*
+Remove Remove the card with the passed ID owned by owner. The name of the card remains in the index until
+every card sharing it has been removed. This should be called whenever a card is deleted
+*/
+func (idx *NameIndex) Remove(owner string, cardId string) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	names, ok := idx.owners[owner]
+	if !ok {
+		return
+	}
+
+	for key, entry := range names.entries {
+		i := slices.Index(entry.cardIds, cardId)
+		if i == -1 {
+			continue
+		}
+
+		entry.cardIds = slices.Delete(entry.cardIds, i, i+1)
+		if len(entry.cardIds) == 0 {
+			delete(names.entries, key)
+
+			j, found := slices.BinarySearch(names.keys, key)
+			if found {
+				names.keys = slices.Delete(names.keys, j, j+1)
+			}
+		}
+
+		return
+	}
+}
+
+/*
+visible Return the names that are visible for owner. If owner is auth.AllOwners, then the names of every
+owner are returned. The caller must hold at least a read lock
+*/
+func (idx *NameIndex) visible(owner string) []*ownerNames {
+	if owner != auth.AllOwners {
+		if names, ok := idx.owners[owner]; ok {
+			return []*ownerNames{names}
+		}
+
+		return nil
+	}
+
+	ret := make([]*ownerNames, 0, len(idx.owners))
+	for _, names := range idx.owners {
+		ret = append(ret, names)
+	}
+
+	return ret
+}
+
+/*
+Autocomplete Return up to limit card names owned by owner that begin with prefix, ignoring case and
+punctuation. Names are returned in alphabetical order
+*/
+func (idx *NameIndex) Autocomplete(owner string, prefix string, limit int) []string {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	key := normalizeName(prefix)
+
+	var matches []*nameEntry
+	for _, names := range idx.visible(owner) {
+		i, _ := slices.BinarySearch(names.keys, key)
+		for end := min(i+limit, len(names.keys)); i < end && strings.HasPrefix(names.keys[i], key); i++ {
+			matches = append(matches, names.entries[names.keys[i]])
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].key < matches[j].key })
+
+	ret := []string{}
+	for i, match := range matches {
+		if len(ret) == limit {
+			break
+		}
+
+		if i > 0 && matches[i-1].key == match.key {
+			continue
+		}
+
+		ret = append(ret, match.name)
+	}
+
+	return ret
+}
+
+/*
+editDistance Return the Levenshtein distance between a and b, or a value greater than limit if the
+distance is known to exceed it
+*/
+func editDistance(a []rune, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+
+		if rowMin > limit {
+			return limit + 1
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+/*
+Fuzzy Resolve a possibly misspelled name to a single card owned by owner. An exact match (ignoring case
+and punctuation) is preferred, followed by the only name beginning with the requested name, followed by
+the name with the smallest edit distance. Names that are further than a quarter of the length of the
+requested name (with a minimum of 2) are not considered. Returns ErrNoCardName if no name is close
+enough, or ErrAmbiguousCardName if more than one name is equally close
+*/
+func (idx *NameIndex) Fuzzy(owner string, name string) (*NameMatch, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	key := normalizeName(name)
+	if key == "" {
+		return nil, ErrNoCardName
+	}
+
+	visible := idx.visible(owner)
+
+	for _, names := range visible {
+		if entry, ok := names.entries[key]; ok {
+			return &NameMatch{Name: entry.name, CardId: entry.cardIds[0], Owner: names.owner}, nil
+		}
+	}
+
+	var prefixed *NameMatch
+	prefixedKeys := make(map[string]bool)
+	for _, names := range visible {
+		i, _ := slices.BinarySearch(names.keys, key)
+		for ; i < len(names.keys) && strings.HasPrefix(names.keys[i], key) && len(prefixedKeys) < 2; i++ {
+			entry := names.entries[names.keys[i]]
+			if !prefixedKeys[entry.key] {
+				prefixedKeys[entry.key] = true
+				prefixed = &NameMatch{Name: entry.name, CardId: entry.cardIds[0], Owner: names.owner}
+			}
+		}
+	}
+
+	if len(prefixedKeys) == 1 {
+		prefixed.Distance = len([]rune(normalizeName(prefixed.Name))) - len([]rune(key))
+		return prefixed, nil
+	}
+
+	target := []rune(key)
+	maxDistance := max(2, len(target)/4)
+
+	var best *NameMatch
+	bestKey := ""
+	ambiguous := false
+	for _, names := range visible {
+		for _, candidate := range names.keys {
+			distance := editDistance(target, []rune(candidate), maxDistance)
+			if distance > maxDistance {
+				continue
+			}
+
+			if best == nil || distance < best.Distance {
+				entry := names.entries[candidate]
+				best = &NameMatch{Name: entry.name, CardId: entry.cardIds[0], Owner: names.owner, Distance: distance}
+				bestKey = candidate
+				ambiguous = false
+			} else if distance == best.Distance && candidate != bestKey {
+				ambiguous = true
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNoCardName
+	}
+
+	if ambiguous {
+		return nil, ErrAmbiguousCardName
+	}
+
+	return best, nil
+}
@@ -0,0 +1,163 @@
+package index
+
+import (
+	"errors"
+	"mtgjson/auth"
+	"reflect"
+	"testing"
+)
+
+const testOwner = "user@example.com"
+
+/*
+testNameIndex Return a name index filled with a small set of system and user owned cards
+*/
+func testNameIndex() *NameIndex {
+	idx := NewNameIndex()
+	idx.Add(auth.SystemOwner, "Lightning Bolt", "bolt")
+	idx.Add(auth.SystemOwner, "Lightning Helix", "helix")
+	idx.Add(auth.SystemOwner, "Shock", "shock")
+	idx.Add(auth.SystemOwner, "Shack", "shack")
+	idx.Add(auth.SystemOwner, "Lim-Dûl's Vault", "vault")
+	idx.Add(auth.SystemOwner, "Æther Vial", "vial")
+	idx.Add(testOwner, "Shock", "user-shock")
+	idx.Add(testOwner, "Custom Card", "custom")
+
+	return idx
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Lightning Bolt", "lightning bolt"},
+		{"  LIGHTNING   bolt ", "lightning bolt"},
+		{"Lim-Dûl's Vault", "lim duls vault"},
+		{"Fire // Ice", "fire ice"},
+		{"Æther Vial", "aether vial"},
+		{"Borrowing 100,000 Arrows", "borrowing 100000 arrows"},
+		{"!!!", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := normalizeName(test.name); got != test.want {
+				t.Errorf("normalizeName(%q) = %q, want %q", test.name, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a     string
+		b     string
+		limit int
+		want  int
+	}{
+		{"kitten", "sitting", 5, 3},
+		{"kitten", "kitten", 5, 0},
+		{"", "abc", 5, 3},
+		{"abc", "", 5, 3},
+		{"flaw", "lawn", 5, 2},
+		{"kitten", "sitting", 2, 3},
+		{"a", "abcdef", 2, 3},
+		{"dûl", "dul", 5, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+"/"+test.b, func(t *testing.T) {
+			if got := editDistance([]rune(test.a), []rune(test.b), test.limit); got != test.want {
+				t.Errorf("editDistance(%q, %q, %d) = %d, want %d", test.a, test.b, test.limit, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFuzzy(t *testing.T) {
+	tests := []struct {
+		owner    string
+		name     string
+		cardId   string
+		distance int
+		err      error
+	}{
+		{auth.SystemOwner, "Lightning Bolt", "bolt", 0, nil},
+		{auth.SystemOwner, "LIGHTNING-BOLT", "bolt", 0, nil},
+		{auth.SystemOwner, "lim duls vault", "vault", 0, nil},
+		{auth.SystemOwner, "aether vial", "vial", 0, nil},
+		{auth.SystemOwner, "lighting bolt", "bolt", 1, nil},
+		{auth.SystemOwner, "lightning b", "bolt", 3, nil},
+		{auth.SystemOwner, "lightning", "", 0, ErrNoCardName},
+		{auth.SystemOwner, "shuck", "", 0, ErrAmbiguousCardName},
+		{auth.SystemOwner, "shick", "", 0, ErrAmbiguousCardName},
+		{auth.SystemOwner, "xyzzy", "", 0, ErrNoCardName},
+		{auth.SystemOwner, "", "", 0, ErrNoCardName},
+		{auth.SystemOwner, "custom card", "", 0, ErrNoCardName},
+		{testOwner, "lightning bolt", "", 0, ErrNoCardName},
+		{testOwner, "shok", "user-shock", 1, nil},
+		{auth.AllOwners, "custom card", "custom", 0, nil},
+	}
+
+	idx := testNameIndex()
+	for _, test := range tests {
+		t.Run(test.owner+"/"+test.name, func(t *testing.T) {
+			match, err := idx.Fuzzy(test.owner, test.name)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Fuzzy(%q) returned error %v, want %v", test.name, err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			if match.CardId != test.cardId || match.Distance != test.distance {
+				t.Errorf("Fuzzy(%q) = %s at distance %d, want %s at distance %d", test.name, match.CardId, match.Distance, test.cardId, test.distance)
+			}
+		})
+	}
+}
+
+func TestAutocomplete(t *testing.T) {
+	tests := []struct {
+		owner  string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{auth.SystemOwner, "light", 10, []string{"Lightning Bolt", "Lightning Helix"}},
+		{auth.SystemOwner, "LIGHTNING h", 10, []string{"Lightning Helix"}},
+		{auth.SystemOwner, "light", 1, []string{"Lightning Bolt"}},
+		{auth.SystemOwner, "lim-dul", 10, []string{"Lim-Dûl's Vault"}},
+		{auth.SystemOwner, "zzz", 10, []string{}},
+		{testOwner, "sh", 10, []string{"Shock"}},
+		{auth.AllOwners, "sh", 10, []string{"Shack", "Shock"}},
+		{"nobody@example.com", "sh", 10, []string{}},
+	}
+
+	idx := testNameIndex()
+	for _, test := range tests {
+		t.Run(test.owner+"/"+test.prefix, func(t *testing.T) {
+			if got := idx.Autocomplete(test.owner, test.prefix, test.limit); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Autocomplete(%q, %d) = %v, want %v", test.prefix, test.limit, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	idx := testNameIndex()
+	idx.Add(auth.SystemOwner, "Lightning Bolt", "bolt-reprint")
+
+	idx.Remove(auth.SystemOwner, "bolt")
+	match, err := idx.Fuzzy(auth.SystemOwner, "lightning bolt")
+	if err != nil || match.CardId != "bolt-reprint" {
+		t.Fatalf("expected the remaining printing to be matched after removing one, got %v, %v", match, err)
+	}
+
+	idx.Remove(auth.SystemOwner, "bolt-reprint")
+	if got := idx.Autocomplete(auth.SystemOwner, "lightning", 10); !reflect.DeepEqual(got, []string{"Lightning Helix"}) {
+		t.Errorf("expected Lightning Bolt to be removed once every printing was removed, got %v", got)
+	}
+}
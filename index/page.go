@@ -0,0 +1,65 @@
+package index
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix - Prefixed to the offset encoded in a cursor, so that arbitrary base64 is not accepted
+const cursorPrefix = "o:"
+
+// ErrInvalidCursor - Returned when a cursor passed by a client cannot be decoded
+var ErrInvalidCursor = errors.New("index: cursor is invalid")
+
+/*
+Page - Describes a single page of an index. Offset is the amount of objects skipped before the page
+begins, and Limit is the maximum amount of objects in the page
+*/
+type Page struct {
+	// Offset - The amount of objects skipped before this page begins
+	Offset int64
+
+	// Limit - The maximum amount of objects returned in this page
+	Limit int64
+}
+
+/*
+Next Return the page following this one
+*/
+func (page Page) Next() Page {
+	return Page{Offset: page.Offset + page.Limit, Limit: page.Limit}
+}
+
+/*
+HasNext Return true if objects remain after this page, given the total amount of objects in the index
+*/
+func (page Page) HasNext(total int64) bool {
+	return page.Offset+page.Limit < total
+}
+
+/*
+EncodeCursor Encode the offset of a page into an opaque cursor that can be handed to clients
+*/
+func EncodeCursor(offset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(offset, 10)))
+}
+
+/*
+DecodeCursor Decode an opaque cursor created with EncodeCursor back into an offset. Returns
+ErrInvalidCursor if the cursor was not created with EncodeCursor
+*/
+func DecodeCursor(cursor string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), cursorPrefix), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,100 @@
+package index
+
+import (
+	"context"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+Printing - A single printing of a card, along with the set that it was printed in
+*/
+type Printing struct {
+	// MtgjsonV4Id - The mtgjsonV4Id of the printing
+	MtgjsonV4Id string `json:"mtgjsonV4Id" bson:"mtgjsonV4Id"`
+
+	// Name - The name of the card
+	Name string `json:"name" bson:"name"`
+
+	// SetCode - The code of the set the card was printed in
+	SetCode string `json:"setCode" bson:"setCode"`
+
+	// SetName - The name of the set the card was printed in. This is empty if the set is not stored
+	SetName string `json:"setName,omitempty" bson:"setName,omitempty"`
+
+	// ReleaseDate - The release date of the set the card was printed in. This is empty if the set is not stored
+	ReleaseDate string `json:"releaseDate,omitempty" bson:"releaseDate,omitempty"`
+
+	// Number - The collector number of the printing
+	Number string `json:"number" bson:"number"`
+
+	// Rarity - The rarity of the printing
+	Rarity string `json:"rarity" bson:"rarity"`
+
+	// FrameVersion - The version of the card frame used by the printing
+	FrameVersion string `json:"frameVersion" bson:"frameVersion"`
+
+	// Finishes - The finishes that the printing is available in (ex. nonfoil, foil, or etched)
+	Finishes []string `json:"finishes" bson:"finishes"`
+}
+
+/*
+Printings Fetch every printing owned by owner that shares the oracle identity of the passed card, including
+the card itself. Printings are matched on their Scryfall oracle ID, or on their name if the card does not
+have one. Each printing is joined with the set it was printed in, and printings are sorted by the release
+date of their set, then by set code and collector number
+*/
+func Printings(server *server.Server, owner string, card *cardModel.CardSet) ([]*Printing, error) {
+	match := bson.M{"name": card.Name}
+	if card.Identifiers != nil && card.Identifiers.ScryfallOracleId != "" {
+		match = bson.M{"identifiers.scryfallOracleId": card.Identifiers.ScryfallOracleId}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"$and": bson.A{OwnerFilter(owner), match}}},
+		bson.M{"$lookup": bson.M{
+			"from": SetCollection,
+			"let":  bson.M{"code": "$setCode", "owner": "$" + OwnerField},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$code", "$$code"}},
+					bson.M{"$eq": bson.A{"$" + OwnerField, "$$owner"}},
+				}}}},
+				bson.M{"$project": bson.M{"name": 1, "releaseDate": 1}},
+				bson.M{"$limit": 1},
+			},
+			"as": "set",
+		}},
+		bson.M{"$project": bson.M{
+			"_id":          0,
+			"mtgjsonV4Id":  "$identifiers.mtgjsonV4Id",
+			"name":         1,
+			"setCode":      1,
+			"number":       1,
+			"rarity":       1,
+			"frameVersion": 1,
+			"finishes":     1,
+			"setName":      bson.M{"$arrayElemAt": bson.A{"$set.name", 0}},
+			"releaseDate":  bson.M{"$arrayElemAt": bson.A{"$set.releaseDate", 0}},
+		}},
+		bson.M{"$sort": bson.D{{Key: "releaseDate", Value: 1}, {Key: "setCode", Value: 1}, {Key: "number", Value: 1}}},
+	}
+
+	ctx := context.Background()
+
+	cursor, err := server.Database().Database().Collection(CardCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	printings := []*Printing{}
+
+	err = cursor.All(ctx, &printings)
+	if err != nil {
+		return nil, err
+	}
+
+	return printings, nil
+}
@@ -0,0 +1,247 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidSort - Returned when a card search is sorted on a field that is not supported
+var ErrInvalidSort = errors.New("index: unsupported sort field")
+
+/*
+sortFields - Maps the sort values accepted by a card search to the fields they sort on
+*/
+var sortFields = map[string]string{
+	"name":      "name",
+	"manaValue": "manaValue",
+	"rarity":    "rarity",
+	"setCode":   "setCode",
+	"number":    "number",
+	"power":     "power",
+	"toughness": "toughness",
+}
+
+/*
+CardSearch - A set of attribute filters applied to a card search. Zero values are ignored, so an
+empty CardSearch matches every card
+*/
+type CardSearch struct {
+	// Name - Matches cards whose name contains this value, ignoring case
+	Name string
+
+	// Text - A full text search across the name, type line and oracle text of the card
+	Text string
+
+	// Type - Matches cards whose type line contains this value, ignoring case
+	Type string
+
+	// Colors - Matches cards that have every one of these colors
+	Colors []string
+
+	// ColorIdentity - Matches cards whose color identity is within these colors (ex. for a commander)
+	ColorIdentity []string
+
+	// ManaValue - Matches cards with exactly this mana value
+	ManaValue *float64
+
+	// ManaValueMin - Matches cards with a mana value greater than or equal to this value
+	ManaValueMin *float64
+
+	// ManaValueMax - Matches cards with a mana value less than or equal to this value
+	ManaValueMax *float64
+
+	// Power - Matches cards with exactly this power (ex. 2 or *)
+	Power string
+
+	// Toughness - Matches cards with exactly this toughness (ex. 2 or *)
+	Toughness string
+
+	// Rarity - Matches cards with this rarity (ex. common or mythic)
+	Rarity string
+
+	// SetCode - Matches cards printed in the set with this code
+	SetCode string
+
+	// Keywords - Matches cards that have every one of these keywords
+	Keywords []string
+
+	// Sort - The field results are sorted on. Prefix with '-' to sort descending. If empty, then results
+	// are sorted by relevance when Text is set, or by name otherwise
+	Sort string
+
+	// Query - A compiled query (see the query package) that results must also match
+	Query bson.M
+}
+
+/*
+containsPattern Return a case-insensitive MongoDB regex matching values that contain value literally
+*/
+func containsPattern(value string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(value), "$options": "i"}
+}
+
+/*
+Filter Build the MongoDB filter for this search, restricted to cards owned by owner
+*/
+func (search CardSearch) Filter(owner string) bson.M {
+	clauses := bson.A{OwnerFilter(owner)}
+
+	if search.Name != "" {
+		clauses = append(clauses, bson.M{"name": containsPattern(search.Name)})
+	}
+
+	if search.Text != "" {
+		clauses = append(clauses, bson.M{"$text": bson.M{"$search": search.Text}})
+	}
+
+	if search.Type != "" {
+		clauses = append(clauses, bson.M{"type": containsPattern(search.Type)})
+	}
+
+	if len(search.Colors) != 0 {
+		clauses = append(clauses, bson.M{"colors": bson.M{"$all": search.Colors}})
+	}
+
+	if len(search.ColorIdentity) != 0 {
+		clauses = append(clauses, bson.M{"colorIdentity": bson.M{"$not": bson.M{"$elemMatch": bson.M{"$nin": search.ColorIdentity}}}})
+	}
+
+	if search.ManaValue != nil {
+		clauses = append(clauses, bson.M{"manaValue": *search.ManaValue})
+	}
+
+	if search.ManaValueMin != nil {
+		clauses = append(clauses, bson.M{"manaValue": bson.M{"$gte": *search.ManaValueMin}})
+	}
+
+	if search.ManaValueMax != nil {
+		clauses = append(clauses, bson.M{"manaValue": bson.M{"$lte": *search.ManaValueMax}})
+	}
+
+	if search.Power != "" {
+		clauses = append(clauses, bson.M{"power": search.Power})
+	}
+
+	if search.Toughness != "" {
+		clauses = append(clauses, bson.M{"toughness": search.Toughness})
+	}
+
+	if search.Rarity != "" {
+		clauses = append(clauses, bson.M{"rarity": strings.ToLower(search.Rarity)})
+	}
+
+	if search.SetCode != "" {
+		clauses = append(clauses, bson.M{"setCode": strings.ToUpper(search.SetCode)})
+	}
+
+	if len(search.Keywords) != 0 {
+		clauses = append(clauses, bson.M{"keywords": bson.M{"$all": search.Keywords}})
+	}
+
+	if len(search.Query) != 0 {
+		clauses = append(clauses, search.Query)
+	}
+
+	return bson.M{"$and": clauses}
+}
+
+/*
+SortSpec Build the MongoDB sort for this search. The MongoDB ID is always appended as a tie-breaker so
+that pages are stable. Returns ErrInvalidSort if the sort field is not supported
+*/
+func (search CardSearch) SortSpec() (bson.D, error) {
+	if search.Sort == "" {
+		if search.Text != "" {
+			return bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}, nil
+		}
+
+		return bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, nil
+	}
+
+	direction := 1
+	field := search.Sort
+	if strings.HasPrefix(field, "-") {
+		direction = -1
+		field = strings.TrimPrefix(field, "-")
+	}
+
+	mapped, ok := sortFields[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, field)
+	}
+
+	return bson.D{{Key: mapped, Value: direction}, {Key: "_id", Value: 1}}, nil
+}
+
+/*
+SearchCards Fetch a page of the cards owned by owner that match the search, along with the total amount
+of matching cards. An empty slice is returned if no cards match
+*/
+func SearchCards(server *server.Server, owner string, search CardSearch, page Page) ([]*cardModel.CardSet, int64, error) {
+	sort, err := search.SortSpec()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results, total, err := find[cardModel.CardSet](server, CardCollection, search.Filter(owner), sort, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+/*
+EnsureCardIndexes Create the text and compound indexes that card searches and identifier lookups depend on.
+Creating an index that already exists does nothing, so this is safe to call each time the API starts
+*/
+func EnsureCardIndexes(server *server.Server) error {
+	models := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "type", Value: "text"}, {Key: "text", Value: "text"}},
+			Options: options.Index().SetName("card_text").SetWeights(bson.M{"name": 10, "type": 3, "text": 1}),
+		},
+		{
+			Keys:    bson.D{{Key: OwnerField, Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("card_owner_name"),
+		},
+		{
+			Keys:    bson.D{{Key: OwnerField, Value: 1}, {Key: "manaValue", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("card_owner_manaValue"),
+		},
+		{
+			Keys:    bson.D{{Key: OwnerField, Value: 1}, {Key: "setCode", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetName("card_owner_setCode_number"),
+		},
+		{
+			Keys:    bson.D{{Key: OwnerField, Value: 1}, {Key: "colorIdentity", Value: 1}},
+			Options: options.Index().SetName("card_owner_colorIdentity"),
+		},
+		{
+			Keys:    bson.D{{Key: OwnerField, Value: 1}, {Key: "rarity", Value: 1}},
+			Options: options.Index().SetName("card_owner_rarity"),
+		},
+		{
+			Keys:    bson.D{{Key: OwnerField, Value: 1}, {Key: "identifiers.scryfallOracleId", Value: 1}},
+			Options: options.Index().SetName("card_owner_scryfallOracleId"),
+		},
+	}
+
+	for _, idType := range IdTypes() {
+		models = append(models, mongo.IndexModel{
+			Keys:    bson.D{{Key: identifierFields[idType], Value: 1}},
+			Options: options.Index().SetName("card_" + idType),
+		})
+	}
+
+	_, err := server.Database().Database().Collection(CardCollection).Indexes().CreateMany(context.Background(), models)
+	return err
+}
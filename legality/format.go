@@ -0,0 +1,153 @@
+package legality
+
+import (
+	"errors"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	"slices"
+)
+
+var (
+	// ErrUnknownFormat - Returned when a deck is validated against a format that is not supported
+	ErrUnknownFormat = errors.New("legality: unsupported format")
+
+	// ErrNotLegal - Returned when a deck that breaks the rules of a format is written under that format
+	ErrNotLegal = errors.New("legality: deck is not legal in the format")
+)
+
+/*
+Format - The construction rules of a single format
+*/
+type Format struct {
+	// Name - The name of the format, as used by the legalities of a card (ex. modern)
+	Name string
+
+	// MinSize - The minimum amount of cards in the main board. For formats with a commander, the commander is
+	// counted as part of the main board
+	MinSize int64
+
+	// MaxSize - The maximum amount of cards in the main board. Zero means that there is no maximum
+	MaxSize int64
+
+	// MaxSideboard - The maximum amount of cards in the sideboard
+	MaxSideboard int64
+
+	// MaxCopies - The maximum amount of copies of any card with the same name, across every board. Formats
+	// with a value of one are singleton formats
+	MaxCopies int64
+
+	// HasCommander - Set to true if decks in the format are led by a commander
+	HasCommander bool
+
+	// CommanderTypes - The card types that can be used as a commander
+	CommanderTypes []string
+
+	// LegendaryCommander - Set to true if the commander must be legendary
+	LegendaryCommander bool
+
+	// legality - Returns the legality of a card in the format
+	legality func(legalities *cardModel.CardLegalities) string
+}
+
+/*
+constructed Return the rules shared by 60 card constructed formats
+*/
+func constructed(name string, legality func(legalities *cardModel.CardLegalities) string) *Format {
+	return &Format{Name: name, MinSize: 60, MaxSideboard: 15, MaxCopies: 4, legality: legality}
+}
+
+/*
+commander Return the rules shared by singleton formats that are led by a legendary commander
+*/
+func commander(name string, size int64, commanderTypes []string, legality func(legalities *cardModel.CardLegalities) string) *Format {
+	return &Format{
+		Name:               name,
+		MinSize:            size,
+		MaxSize:            size,
+		MaxCopies:          1,
+		HasCommander:       true,
+		CommanderTypes:     commanderTypes,
+		LegendaryCommander: true,
+		legality:           legality,
+	}
+}
+
+/*
+formats - The formats that decks can be validated against, keyed by name
+*/
+var formats = map[string]*Format{
+	"standard":  constructed("standard", func(l *cardModel.CardLegalities) string { return l.Standard }),
+	"pioneer":   constructed("pioneer", func(l *cardModel.CardLegalities) string { return l.Pioneer }),
+	"modern":    constructed("modern", func(l *cardModel.CardLegalities) string { return l.Modern }),
+	"legacy":    constructed("legacy", func(l *cardModel.CardLegalities) string { return l.Legacy }),
+	"vintage":   constructed("vintage", func(l *cardModel.CardLegalities) string { return l.Vintage }),
+	"pauper":    constructed("pauper", func(l *cardModel.CardLegalities) string { return l.Pauper }),
+	"penny":     constructed("penny", func(l *cardModel.CardLegalities) string { return l.Penny }),
+	"premodern": constructed("premodern", func(l *cardModel.CardLegalities) string { return l.Premodern }),
+	"oldschool": constructed("oldschool", func(l *cardModel.CardLegalities) string { return l.Oldschool }),
+	"historic":  constructed("historic", func(l *cardModel.CardLegalities) string { return l.Historic }),
+	"explorer":  constructed("explorer", func(l *cardModel.CardLegalities) string { return l.Explorer }),
+	"timeless":  constructed("timeless", func(l *cardModel.CardLegalities) string { return l.Timeless }),
+	"alchemy":   constructed("alchemy", func(l *cardModel.CardLegalities) string { return l.Alchemy }),
+	"future":    constructed("future", func(l *cardModel.CardLegalities) string { return l.Future }),
+
+	"commander": commander("commander", 100, []string{"Creature"}, func(l *cardModel.CardLegalities) string { return l.Commander }),
+	"duel":      commander("duel", 100, []string{"Creature"}, func(l *cardModel.CardLegalities) string { return l.Duel }),
+	"predh":     commander("predh", 100, []string{"Creature"}, func(l *cardModel.CardLegalities) string { return l.Predh }),
+	"brawl":     commander("brawl", 100, []string{"Creature", "Planeswalker"}, func(l *cardModel.CardLegalities) string { return l.Brawl }),
+	"standardbrawl": commander("standardbrawl", 60, []string{"Creature", "Planeswalker"}, func(l *cardModel.CardLegalities) string {
+		return l.Standardbrawl
+	}),
+	"paupercommander": {
+		Name:           "paupercommander",
+		MinSize:        100,
+		MaxSize:        100,
+		MaxCopies:      1,
+		HasCommander:   true,
+		CommanderTypes: []string{"Creature"},
+		legality:       func(l *cardModel.CardLegalities) string { return l.Paupercommander },
+	},
+	"gladiator": {
+		Name:      "gladiator",
+		MinSize:   100,
+		MaxSize:   100,
+		MaxCopies: 1,
+		legality:  func(l *cardModel.CardLegalities) string { return l.Gladiator },
+	},
+}
+
+/*
+GetFormat Return the rules of a format by its name. Returns ErrUnknownFormat if the format is not supported
+*/
+func GetFormat(name string) (*Format, error) {
+	format, ok := formats[name]
+	if !ok {
+		return nil, ErrUnknownFormat
+	}
+
+	return format, nil
+}
+
+/*
+Formats Return the names of the formats that decks can be validated against, in alphabetical order
+*/
+func Formats() []string {
+	ret := make([]string, 0, len(formats))
+	for name := range formats {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+
+	return ret
+}
+
+/*
+Legality Return the legality of a card in the format (ex. Legal, Banned or Restricted). An empty string is
+returned if the card is not legal in the format
+*/
+func (format *Format) Legality(card *cardModel.CardDeck) string {
+	if card.Legalities == nil {
+		return ""
+	}
+
+	return format.legality(card.Legalities)
+}
@@ -0,0 +1,342 @@
+package legality
+
+import (
+	"fmt"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// RuleDeckSize - The main board has too few or too many cards
+	RuleDeckSize = "deckSize"
+
+	// RuleSideboardSize - The sideboard has too many cards
+	RuleSideboardSize = "sideboardSize"
+
+	// RuleCopyLimit - The deck has more copies of a card than the format allows
+	RuleCopyLimit = "copyLimit"
+
+	// RuleSingleton - The deck has more than one copy of a card in a singleton format
+	RuleSingleton = "singleton"
+
+	// RuleNotLegal - A card is not legal in the format
+	RuleNotLegal = "notLegal"
+
+	// RuleBanned - A card is banned in the format
+	RuleBanned = "banned"
+
+	// RuleRestricted - The deck has more than one copy of a card that is restricted in the format
+	RuleRestricted = "restricted"
+
+	// RuleCommander - The commander of the deck is missing or cannot be used as a commander
+	RuleCommander = "commander"
+
+	// RuleColorIdentity - A card is outside the color identity of the deck's commander
+	RuleColorIdentity = "colorIdentity"
+)
+
+/*
+Violation - A single construction rule that a deck breaks
+*/
+type Violation struct {
+	// Rule - The rule that was broken (ex. copyLimit)
+	Rule string `json:"rule"`
+
+	// Message - A human-readable explanation of the violation
+	Message string `json:"message"`
+
+	// Card - The name of the card that breaks the rule. This is empty for rules that apply to the deck as a whole
+	Card string `json:"card,omitempty"`
+
+	// Board - The board of the card that breaks the rule
+	Board string `json:"board,omitempty"`
+
+	// Incomplete - Set to true if the violation only means that the deck is not finished yet, such as having
+	// too few cards or no commander. These do not prevent a deck from being written under its format
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+/*
+Result - The result of validating a deck against a format
+*/
+type Result struct {
+	// Format - The format the deck was validated against
+	Format string `json:"format"`
+
+	// Legal - Set to true if the deck does not break any rules of the format
+	Legal bool `json:"legal"`
+
+	// Violations - Every rule that the deck breaks
+	Violations []*Violation `json:"violations"`
+}
+
+/*
+Blocking Return the violations that are not caused by the deck being incomplete. A deck with blocking
+violations cannot become legal by adding cards to it
+*/
+func (result *Result) Blocking() []*Violation {
+	ret := []*Violation{}
+	for _, violation := range result.Violations {
+		if !violation.Incomplete {
+			ret = append(ret, violation)
+		}
+	}
+
+	return ret
+}
+
+/*
+anyNumberPattern - Matches the rules text of cards that are exempt from the copy limit of a format
+(ex. A deck can have any number of cards named Relentless Rats)
+*/
+var anyNumberPattern = regexp.MustCompile(`A deck can have (?:any number of|up to (\w+)) cards named`)
+
+/*
+numberWords - The copy limits that cards declare in their rules text
+*/
+var numberWords = map[string]int64{"seven": 7, "nine": 9}
+
+/*
+copyLimit Return the maximum amount of copies of a card allowed in the format. Basic lands and cards that
+declare their own limit are exempt from the limit of the format, and restricted cards are limited to one.
+A value of -1 means that any number of copies are allowed
+*/
+func (format *Format) copyLimit(card *cardModel.CardDeck) int64 {
+	if slices.Contains(card.Supertypes, "Basic") {
+		return -1
+	}
+
+	match := anyNumberPattern.FindStringSubmatch(card.Text)
+	if match != nil {
+		if limit, ok := numberWords[match[1]]; ok {
+			return limit
+		}
+		return -1
+	}
+
+	if format.Legality(card) == "Restricted" {
+		return 1
+	}
+
+	return format.MaxCopies
+}
+
+/*
+canBeCommander Return true if a card can be used as the commander of a deck in the format
+*/
+func (format *Format) canBeCommander(card *cardModel.CardDeck) bool {
+	if strings.Contains(card.Text, "can be your commander") {
+		return true
+	}
+
+	if format.LegendaryCommander && !slices.Contains(card.Supertypes, "Legendary") {
+		return false
+	}
+
+	for _, cardType := range format.CommanderTypes {
+		if slices.Contains(card.Types, cardType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+canPartner Return true if a card allows a second commander to be used alongside it
+*/
+func canPartner(card *cardModel.CardDeck) bool {
+	for _, keyword := range card.Keywords {
+		switch keyword {
+		case "Partner", "Partner with", "Friends forever", "Choose a Background", "Doctor's companion":
+			return true
+		}
+	}
+
+	return slices.Contains(card.Subtypes, "Background")
+}
+
+/*
+plural Return singular if count is one, otherwise return plural
+*/
+func plural(count int64, singular string, plural string) string {
+	if count == 1 {
+		return singular
+	}
+
+	return plural
+}
+
+/*
+sumCounts Return the total amount of cards in a board
+*/
+func sumCounts(cards []*cardModel.CardDeck) int64 {
+	var total int64
+	for _, card := range cards {
+		total += card.Count
+	}
+
+	return total
+}
+
+/*
+Validate Validate the contents of a deck against the construction rules of a format. Every rule that the
+deck breaks is returned, rather than stopping at the first. Returns ErrUnknownFormat if the format is not
+supported
+*/
+func Validate(contents *deckModel.DeckContents, name string) (*Result, error) {
+	format, err := GetFormat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Format: format.Name, Violations: []*Violation{}}
+	violate := func(violation *Violation) {
+		result.Violations = append(result.Violations, violation)
+	}
+
+	size := sumCounts(contents.MainBoard)
+	if format.HasCommander {
+		size += sumCounts(contents.Commander)
+	}
+
+	if format.MaxSize != 0 && format.MinSize == format.MaxSize && size != format.MinSize {
+		violate(&Violation{Rule: RuleDeckSize, Message: fmt.Sprintf("The deck must contain exactly %d cards, but contains %d", format.MinSize, size), Incomplete: size < format.MinSize})
+	} else if size < format.MinSize {
+		violate(&Violation{Rule: RuleDeckSize, Message: fmt.Sprintf("The deck must contain at least %d cards, but contains %d", format.MinSize, size), Incomplete: true})
+	} else if format.MaxSize != 0 && size > format.MaxSize {
+		violate(&Violation{Rule: RuleDeckSize, Message: fmt.Sprintf("The deck must contain at most %d cards, but contains %d", format.MaxSize, size)})
+	}
+
+	sideboard := sumCounts(contents.SideBoard)
+	if sideboard > format.MaxSideboard {
+		message := fmt.Sprintf("The sideboard must contain at most %d cards, but contains %d", format.MaxSideboard, sideboard)
+		if format.MaxSideboard == 0 {
+			message = fmt.Sprintf("Decks in %s cannot have a sideboard, but the sideboard contains %d cards", format.Name, sideboard)
+		}
+		violate(&Violation{Rule: RuleSideboardSize, Message: message})
+	}
+
+	var identity []string
+	if format.HasCommander {
+		identity = validateCommanders(format, contents.Commander, violate)
+	} else if len(contents.Commander) != 0 {
+		violate(&Violation{Rule: RuleCommander, Message: fmt.Sprintf("Decks in %s do not have a commander", format.Name)})
+	}
+
+	boards := []struct {
+		name  string
+		cards []*cardModel.CardDeck
+	}{
+		{"commander", contents.Commander},
+		{"mainBoard", contents.MainBoard},
+		{"sideBoard", contents.SideBoard},
+	}
+
+	copies := make(map[string]int64)
+	var names []string
+	byName := make(map[string]*cardModel.CardDeck)
+	checked := make(map[string]bool)
+
+	for _, board := range boards {
+		for _, card := range board.cards {
+			if _, ok := copies[card.Name]; !ok {
+				names = append(names, card.Name)
+				byName[card.Name] = card
+			}
+			copies[card.Name] += card.Count
+
+			if checked[board.name+card.Name] {
+				continue
+			}
+			checked[board.name+card.Name] = true
+
+			switch format.Legality(card) {
+			case "Legal", "Restricted":
+			case "Banned":
+				violate(&Violation{Rule: RuleBanned, Message: fmt.Sprintf("%s is banned in %s", card.Name, format.Name), Card: card.Name, Board: board.name})
+			default:
+				violate(&Violation{Rule: RuleNotLegal, Message: fmt.Sprintf("%s is not legal in %s", card.Name, format.Name), Card: card.Name, Board: board.name})
+			}
+
+			if identity != nil && board.name != "commander" {
+				for _, color := range card.ColorIdentity {
+					if !slices.Contains(identity, color) {
+						violate(&Violation{
+							Rule:    RuleColorIdentity,
+							Message: fmt.Sprintf("%s is outside the color identity of the commander", card.Name),
+							Card:    card.Name,
+							Board:   board.name,
+						})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for _, cardName := range names {
+		card := byName[cardName]
+
+		limit := format.copyLimit(card)
+		if limit < 0 || copies[cardName] <= limit {
+			continue
+		}
+
+		rule := RuleCopyLimit
+		if format.Legality(card) == "Restricted" {
+			rule = RuleRestricted
+		} else if format.MaxCopies == 1 && limit == 1 {
+			rule = RuleSingleton
+		}
+
+		violate(&Violation{
+			Rule:    rule,
+			Message: fmt.Sprintf("The deck may contain at most %d %s of %s, but contains %d", limit, plural(limit, "copy", "copies"), cardName, copies[cardName]),
+			Card:    cardName,
+		})
+	}
+
+	result.Legal = len(result.Violations) == 0
+
+	return result, nil
+}
+
+/*
+validateCommanders Validate the commanders of a deck, and return their combined color identity. A deck must
+have one commander, or two if both of them allow a partner. A nil identity is returned if the deck does
+not have a commander, so that color identity is not checked
+*/
+func validateCommanders(format *Format, commanders []*cardModel.CardDeck, violate func(*Violation)) []string {
+	count := sumCounts(commanders)
+	if count == 0 {
+		violate(&Violation{Rule: RuleCommander, Message: fmt.Sprintf("Decks in %s must have a commander", format.Name), Incomplete: true})
+		return nil
+	}
+
+	if count > 2 {
+		violate(&Violation{Rule: RuleCommander, Message: fmt.Sprintf("A deck may have at most 2 commanders, but has %d", count)})
+	}
+
+	identity := []string{}
+	for _, card := range commanders {
+		if !format.canBeCommander(card) {
+			violate(&Violation{Rule: RuleCommander, Message: fmt.Sprintf("%s cannot be used as a commander in %s", card.Name, format.Name), Card: card.Name, Board: "commander"})
+		}
+
+		if count == 2 && !canPartner(card) {
+			violate(&Violation{Rule: RuleCommander, Message: fmt.Sprintf("%s cannot be used alongside a second commander", card.Name), Card: card.Name, Board: "commander"})
+		}
+
+		for _, color := range card.ColorIdentity {
+			if !slices.Contains(identity, color) {
+				identity = append(identity, color)
+			}
+		}
+	}
+
+	return identity
+}
@@ -0,0 +1,361 @@
+package legality
+
+import (
+	"errors"
+	"fmt"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+/*
+testCard Return a card that has the passed legality in every format used by the tests
+*/
+func testCard(name string, count int64, legality string) *cardModel.CardDeck {
+	return &cardModel.CardDeck{
+		Name:       name,
+		Count:      count,
+		Types:      []string{"Instant"},
+		Legalities: &cardModel.CardLegalities{Modern: legality, Vintage: legality, Commander: legality},
+	}
+}
+
+/*
+testCommander Return a legendary creature with the passed color identity and keywords
+*/
+func testCommander(name string, identity []string, keywords ...string) *cardModel.CardDeck {
+	card := testCard(name, 1, "Legal")
+	card.Types = []string{"Creature"}
+	card.Supertypes = []string{"Legendary"}
+	card.ColorIdentity = identity
+	card.Keywords = keywords
+
+	return card
+}
+
+/*
+filler Return colorless cards named after prefix that are legal everywhere, totalling count cards with at
+most copies of each
+*/
+func filler(prefix string, count int64, copies int64) []*cardModel.CardDeck {
+	cards := []*cardModel.CardDeck{}
+	for i := 1; count > 0; i++ {
+		n := min(count, copies)
+		cards = append(cards, testCard(fmt.Sprintf("%s %d", prefix, i), n, "Legal"))
+		count -= n
+	}
+
+	return cards
+}
+
+/*
+withText Return card with its rules text set
+*/
+func withText(card *cardModel.CardDeck, text string) *cardModel.CardDeck {
+	card.Text = text
+	return card
+}
+
+/*
+withIdentity Return card with its color identity set
+*/
+func withIdentity(card *cardModel.CardDeck, identity ...string) *cardModel.CardDeck {
+	card.ColorIdentity = identity
+	return card
+}
+
+func TestValidate(t *testing.T) {
+	mountain := testCard("Mountain", 20, "Legal")
+	mountain.Supertypes = []string{"Basic"}
+	mountain.Types = []string{"Land"}
+
+	nonLegendary := testCommander("Goblin Guide", []string{"R"})
+	nonLegendary.Supertypes = nil
+
+	planeswalker := withText(testCommander("Nissa, Vastwood Seer", []string{"G"}), "Nissa, Vastwood Seer can be your commander.")
+	planeswalker.Types = []string{"Planeswalker"}
+
+	tests := []struct {
+		name     string
+		format   string
+		contents *deckModel.DeckContents
+		want     []string
+		blocking int
+	}{
+		{
+			name:     "legal constructed deck",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 60, 4), SideBoard: filler("Sideboard", 15, 4)},
+			want:     []string{},
+		},
+		{
+			name:     "too few cards is incomplete",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 59, 4)},
+			want:     []string{"deckSize"},
+			blocking: 0,
+		},
+		{
+			name:     "constructed decks have no maximum size",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 250, 4)},
+			want:     []string{},
+		},
+		{
+			name:     "too many sideboard cards",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 60, 4), SideBoard: filler("Sideboard", 16, 4)},
+			want:     []string{"sideboardSize"},
+			blocking: 1,
+		},
+		{
+			name:     "copy limit",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 55, 4), testCard("Lightning Bolt", 5, "Legal"))},
+			want:     []string{"copyLimit:Lightning Bolt"},
+			blocking: 1,
+		},
+		{
+			name:   "copy limit counts every board",
+			format: "modern",
+			contents: &deckModel.DeckContents{
+				MainBoard: append(filler("Filler", 57, 4), testCard("Lightning Bolt", 3, "Legal")),
+				SideBoard: []*cardModel.CardDeck{testCard("Lightning Bolt", 2, "Legal")},
+			},
+			want:     []string{"copyLimit:Lightning Bolt"},
+			blocking: 1,
+		},
+		{
+			name:     "basic lands are exempt from the copy limit",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 40, 4), mountain)},
+			want:     []string{},
+		},
+		{
+			name:   "any number of",
+			format: "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 40, 4),
+				withText(testCard("Relentless Rats", 20, "Legal"), "A deck can have any number of cards named Relentless Rats."))},
+			want: []string{},
+		},
+		{
+			name:   "up to seven",
+			format: "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 53, 4),
+				withText(testCard("Seven Dwarves", 7, "Legal"), "A deck can have up to seven cards named Seven Dwarves."))},
+			want: []string{},
+		},
+		{
+			name:   "more than seven",
+			format: "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 52, 4),
+				withText(testCard("Seven Dwarves", 8, "Legal"), "A deck can have up to seven cards named Seven Dwarves."))},
+			want:     []string{"copyLimit:Seven Dwarves"},
+			blocking: 1,
+		},
+		{
+			name:     "banned",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 56, 4), testCard("Splinter Twin", 4, "Banned"))},
+			want:     []string{"banned:Splinter Twin"},
+			blocking: 1,
+		},
+		{
+			name:     "not legal",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 56, 4), testCard("Black Lotus", 4, ""))},
+			want:     []string{"notLegal:Black Lotus"},
+			blocking: 1,
+		},
+		{
+			name:     "single restricted card",
+			format:   "vintage",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 59, 4), testCard("Black Lotus", 1, "Restricted"))},
+			want:     []string{},
+		},
+		{
+			name:     "restricted",
+			format:   "vintage",
+			contents: &deckModel.DeckContents{MainBoard: append(filler("Filler", 58, 4), testCard("Black Lotus", 2, "Restricted"))},
+			want:     []string{"restricted:Black Lotus"},
+			blocking: 1,
+		},
+		{
+			name:     "commander outside of a commander format",
+			format:   "modern",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 60, 4), Commander: []*cardModel.CardDeck{testCommander("Krenko, Mob Boss", []string{"R"})}},
+			want:     []string{"commander"},
+			blocking: 1,
+		},
+		{
+			name:     "legal commander deck",
+			format:   "commander",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 99, 1), Commander: []*cardModel.CardDeck{testCommander("Krenko, Mob Boss", []string{"R"})}},
+			want:     []string{},
+		},
+		{
+			name:     "missing commander is incomplete",
+			format:   "commander",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 99, 1)},
+			want:     []string{"deckSize", "commander"},
+			blocking: 0,
+		},
+		{
+			name:     "too many cards in a commander deck",
+			format:   "commander",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 100, 1), Commander: []*cardModel.CardDeck{testCommander("Krenko, Mob Boss", []string{"R"})}},
+			want:     []string{"deckSize"},
+			blocking: 1,
+		},
+		{
+			name:   "singleton",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: append(filler("Filler", 97, 1), testCard("Sol Ring", 2, "Legal")),
+				Commander: []*cardModel.CardDeck{testCommander("Krenko, Mob Boss", []string{"R"})},
+			},
+			want:     []string{"singleton:Sol Ring"},
+			blocking: 1,
+		},
+		{
+			name:   "basic lands are exempt from singleton",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: append(filler("Filler", 79, 1), mountain),
+				Commander: []*cardModel.CardDeck{testCommander("Krenko, Mob Boss", []string{"R"})},
+			},
+			want: []string{},
+		},
+		{
+			name:   "partner commanders",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: append(filler("Filler", 96, 1), withIdentity(testCard("Counterspell", 1, "Legal"), "U"), withIdentity(testCard("Swords to Plowshares", 1, "Legal"), "W")),
+				Commander: []*cardModel.CardDeck{
+					testCommander("Thrasios, Triton Hero", []string{"G", "U"}, "Partner"),
+					testCommander("Tymna the Weaver", []string{"W", "B"}, "Partner"),
+				},
+			},
+			want: []string{},
+		},
+		{
+			name:   "commander with a background",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: filler("Filler", 98, 1),
+				Commander: []*cardModel.CardDeck{
+					testCommander("Wilson, Refined Grizzly", []string{"G"}, "Choose a Background"),
+					func() *cardModel.CardDeck {
+						card := testCommander("Raised by Giants", []string{"G"})
+						card.Types = []string{"Enchantment"}
+						card.Subtypes = []string{"Background"}
+						return withText(card, "Raised by Giants can be your commander.")
+					}(),
+				},
+			},
+			want: []string{},
+		},
+		{
+			name:   "two commanders without partner",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: filler("Filler", 98, 1),
+				Commander: []*cardModel.CardDeck{
+					testCommander("Krenko, Mob Boss", []string{"R"}),
+					testCommander("Thrasios, Triton Hero", []string{"G", "U"}, "Partner"),
+				},
+			},
+			want:     []string{"commander:Krenko, Mob Boss"},
+			blocking: 1,
+		},
+		{
+			name:   "three commanders",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: filler("Filler", 97, 1),
+				Commander: []*cardModel.CardDeck{
+					testCommander("Thrasios, Triton Hero", []string{"G", "U"}, "Partner"),
+					testCommander("Tymna the Weaver", []string{"W", "B"}, "Partner"),
+					testCommander("Kraum, Ludevic's Opus", []string{"U", "R"}, "Partner"),
+				},
+			},
+			want:     []string{"commander"},
+			blocking: 1,
+		},
+		{
+			name:   "color identity",
+			format: "commander",
+			contents: &deckModel.DeckContents{
+				MainBoard: append(filler("Filler", 98, 1), withIdentity(testCard("Counterspell", 1, "Legal"), "U")),
+				Commander: []*cardModel.CardDeck{testCommander("Krenko, Mob Boss", []string{"R"})},
+			},
+			want:     []string{"colorIdentity:Counterspell"},
+			blocking: 1,
+		},
+		{
+			name:     "commander must be legendary",
+			format:   "commander",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 99, 1), Commander: []*cardModel.CardDeck{nonLegendary}},
+			want:     []string{"commander:Goblin Guide"},
+			blocking: 1,
+		},
+		{
+			name:     "card that can be your commander",
+			format:   "commander",
+			contents: &deckModel.DeckContents{MainBoard: filler("Filler", 99, 1), Commander: []*cardModel.CardDeck{planeswalker}},
+			want:     []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Validate(test.contents, test.format)
+			if err != nil {
+				t.Fatalf("Validate() returned an error: %v", err)
+			}
+
+			got := []string{}
+			for _, violation := range result.Violations {
+				rule := violation.Rule
+				if violation.Card != "" {
+					rule += ":" + violation.Card
+				}
+				got = append(got, rule)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Validate() violations = %q, want %q", got, test.want)
+			}
+
+			if result.Legal != (len(test.want) == 0) {
+				t.Errorf("Validate() legal = %v with %d violations", result.Legal, len(got))
+			}
+
+			if blocking := len(result.Blocking()); blocking != test.blocking {
+				t.Errorf("Blocking() returned %d violations, want %d", blocking, test.blocking)
+			}
+		})
+	}
+}
+
+func TestValidateUnknownFormat(t *testing.T) {
+	_, err := Validate(&deckModel.DeckContents{}, "casual")
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Validate() returned %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	names := Formats()
+	if !slices.IsSorted(names) {
+		t.Errorf("Formats() is not sorted: %v", names)
+	}
+
+	for _, name := range names {
+		if _, err := GetFormat(name); err != nil {
+			t.Errorf("GetFormat(%q) returned an error: %v", name, err)
+		}
+	}
+}
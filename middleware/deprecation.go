@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+/*
+DeprecationHandler Gin handler for marking a route as deprecated. A Deprecation header is set on
+every response holding the date the route was deprecated, formatted as a structured field date
+(ex. @1792195200) as described in RFC 9745. If the sunset parameter is not empty, then it is returned
+in the Sunset header. If the successor parameter is not empty, then a Link header is returned pointing
+clients to the version of the API that they should migrate to
+*/
+func DeprecationHandler(deprecatedAt time.Time, sunset string, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", deprecation)
+
+		if sunset != "" {
+			ctx.Header("Sunset", sunset)
+		}
+
+		if successor != "" {
+			ctx.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		}
+
+		ctx.Next()
+	}
+}
@@ -2,20 +2,52 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/gin-gonic/gin"
 	"github.com/stevezaluk/mtgjson-sdk/server"
 	"mtgjson/auth"
 	"net/http"
 	"strings"
+	"time"
 )
 
 /*
-ValidateTokenHandler Gin handler for validating tokens received from your Auth0 tenant. An Authorization header is
+ValidateTokenHandler Gin handler for validating tokens received from your authentication provider. An Authorization header is
 required to be passed in the request for this to properly function. If the token is valid, then it
-is stored in the gin context under 'token'. If the token is invalid, the request is aborted.
+is stored in the gin context under 'token'. If the token is invalid, the request is aborted. The
+tokenValidator parameter is shared across all requests, so that JWKS are not re-fetched for each request.
+
+The caller's email is read from the token claims. If the token does not carry an email claim, then it is
+fetched from the authentication provider instead, and cached in identityCache if it is not nil.
+
+If an X-API-Key header is passed instead of an Authorization header, then the API key is validated
+and the owner and scopes of the key are stored in the gin context in place of the token's
 */
-func ValidateTokenHandler(server *server.Server) gin.HandlerFunc {
+func ValidateTokenHandler(server *server.Server, provider auth.Provider, tokenValidator *validator.Validator, identityCache *auth.IdentityCache) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if rawKey := ctx.GetHeader("X-API-Key"); rawKey != "" {
+			key, err := auth.ValidateAPIKey(server, rawKey)
+			if errors.Is(err, auth.ErrInvalidAPIKey) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"message": "API key is not valid", "err": err.Error()})
+				ctx.Abort()
+				return
+			} else if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to validate API key", "err": err.Error()})
+				ctx.Abort()
+				return
+			}
+
+			ctx.Set("userEmail", key.Owner)
+			ctx.Set("userSubject", key.Owner)
+			ctx.Set("token", &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{Subject: key.Owner},
+				CustomClaims:     &auth.CustomClaims{Scope: key.Scope, Email: key.Owner},
+			})
+			ctx.Set("apiKeyId", key.KeyId)
+			return
+		}
+
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"message": "Authorization header is missing from request"}) // format this better
@@ -25,13 +57,6 @@ func ValidateTokenHandler(server *server.Server) gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-		tokenValidator, err := auth.GetTokenValidator()
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to start token validator", "err": err.Error()}) // format this better
-			ctx.Abort()
-			return
-		}
-
 		token, err := tokenValidator.ValidateToken(
 			context.Background(),
 			tokenStr,
@@ -43,14 +68,28 @@ func ValidateTokenHandler(server *server.Server) gin.HandlerFunc {
 			return
 		}
 
-		userEmail, err := server.AuthenticationManager().GetEmailFromToken(tokenStr)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch email from access token. This is needed for establishing ownership in created objects", "err": err.Error()})
-			ctx.Abort()
-			return
+		validatedClaims := token.(*validator.ValidatedClaims)
+
+		userEmail := validatedClaims.CustomClaims.(*auth.CustomClaims).Email
+		if userEmail == "" && identityCache != nil {
+			userEmail, _ = identityCache.Get(tokenStr)
+		}
+
+		if userEmail == "" {
+			userEmail, err = provider.GetEmailFromToken(tokenStr)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch email from access token. This is needed for establishing ownership in created objects", "err": err.Error()})
+				ctx.Abort()
+				return
+			}
+
+			if identityCache != nil {
+				identityCache.Set(tokenStr, userEmail, time.Unix(validatedClaims.RegisteredClaims.Expiry, 0))
+			}
 		}
 
 		ctx.Set("userEmail", userEmail)
+		ctx.Set("userSubject", validatedClaims.RegisteredClaims.Subject)
 		ctx.Set("token", token)
 		ctx.Set("tokenStr", tokenStr)
 	}
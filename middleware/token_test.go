@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gopkg.in/go-jose/go-jose.v2"
+	"gopkg.in/go-jose/go-jose.v2/jwt"
+	"mtgjson/auth"
+)
+
+/*
+userinfoProvider Wraps the provider built by auth.NewProvider, fetching the caller's email from the userinfo
+endpoint of the test server in place of the Auth0 authentication manager
+*/
+type userinfoProvider struct {
+	auth.Provider
+
+	userinfoUrl string
+	client      *http.Client
+}
+
+func (provider *userinfoProvider) GetEmailFromToken(token string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, provider.userinfoUrl, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := provider.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var userinfo struct {
+		Email string `json:"email"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&userinfo)
+	return userinfo.Email, err
+}
+
+/*
+BenchmarkValidateTokenHandler Serve authenticated requests through a gin router using the provider, token validator
+and identity cache built the same way as api.New, and verify that the JWKS and userinfo endpoints of the tenant
+are each only requested once regardless of how many requests are served
+*/
+func BenchmarkValidateTokenHandler(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var jwksFetches, userinfoFetches atomic.Int64
+
+	mux := http.NewServeMux()
+	tenant := httptest.NewTLSServer(mux)
+	defer tenant.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":   tenant.URL + "/",
+			"jwks_uri": tenant.URL + "/.well-known/jwks.json",
+		})
+	})
+
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		jwksFetches.Add(1)
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &privateKey.PublicKey, KeyID: "bench", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		userinfoFetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]string{"email": "user@example.com"})
+	})
+
+	// the JWKS provider uses a default HTTP client, so it needs to trust the certificate of the test server
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = tenant.Client().Transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	tenantUrl, err := url.Parse(tenant.URL)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	viper.Set("auth0.domain", tenantUrl.Host)
+	viper.Set("auth0.audience", "mtgjson-api")
+	viper.Set("auth.jwks_cache_ttl", 60)
+	defer viper.Reset()
+
+	provider, err := auth.NewProvider(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	tokenValidator, err := auth.NewTokenValidator(provider)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	identityCache := auth.NewIdentityCache(time.Hour)
+
+	router := gin.New()
+	router.GET("/card", ValidateTokenHandler(nil, &userinfoProvider{provider, tenant.URL + "/userinfo", tenant.Client()}, tokenValidator, identityCache), func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.GetString("userEmail"))
+	})
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: privateKey},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "bench"),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	now := time.Now()
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   tenant.URL + "/",
+		Subject:  "auth0|bench",
+		Audience: jwt.Audience{"mtgjson-api"},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}).Claims(map[string]interface{}{"scope": "read:card.wotc"}).CompactSerialize()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/card", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "user@example.com" {
+			b.Fatalf("request returned %d: %s", w.Code, w.Body.String())
+		}
+	}
+	b.StopTimer()
+
+	if fetched := jwksFetches.Load(); fetched != 1 {
+		b.Fatalf("JWKS was fetched %d times while serving %d requests, expected it to be fetched once", fetched, b.N)
+	}
+
+	if fetched := userinfoFetches.Load(); fetched != 1 {
+		b.Fatalf("userinfo was fetched %d times while serving %d requests, expected it to be fetched once", fetched, b.N)
+	}
+
+	b.ReportMetric(float64(jwksFetches.Load()), "jwks-fetches")
+	b.ReportMetric(float64(userinfoFetches.Load()), "userinfo-fetches")
+}
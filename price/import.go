@@ -0,0 +1,287 @@
+package price
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// batchSize - The amount of price points inserted into MongoDB at once during an import
+const batchSize = 5000
+
+/*
+providerPrices - The prices published by a single provider for a card, as they appear in MTGJSON price
+files. Retail and Buylist map a finish to a map of dates to prices
+*/
+type providerPrices struct {
+	Currency string                        `json:"currency"`
+	Retail   map[string]map[string]float64 `json:"retail"`
+	Buylist  map[string]map[string]float64 `json:"buylist"`
+}
+
+/*
+cardPrices - The prices of a single card, as they appear in MTGJSON price files. Maps a game availability
+(ex. paper) to a map of providers to their prices
+*/
+type cardPrices map[string]map[string]providerPrices
+
+/*
+ImportStats - Describes the result of an import
+*/
+type ImportStats struct {
+	// Cards - The amount of cards that prices were read for
+	Cards int64
+
+	// Points - The amount of price points inserted
+	Points int64
+
+	// Skipped - The amount of price points that were skipped, as a price was already stored for the same
+	// series and day
+	Skipped int64
+}
+
+/*
+importer - Buffers price points read from a price file, and inserts them into MongoDB in batches
+*/
+type importer struct {
+	server *server.Server
+	stats  ImportStats
+
+	// batch - The price points read since the last flush
+	batch []Point
+
+	// uuids - The cards that the buffered price points belong to
+	uuids map[string]bool
+
+	// from - The earliest date of the buffered price points
+	from time.Time
+}
+
+/*
+flush Insert the buffered price points that are not already stored into MongoDB. The points already stored
+for the buffered cards are looked up in a single query, rather than once for each card. Points are compared
+against the stored points of each series individually, so re-importing a file after a failed import fills
+in the points that were not written
+*/
+func (imp *importer) flush() error {
+	if len(imp.batch) == 0 {
+		return nil
+	}
+
+	uuids := make([]string, 0, len(imp.uuids))
+	for uuid := range imp.uuids {
+		uuids = append(uuids, uuid)
+	}
+
+	stored, err := StoredKeys(imp.server, uuids, imp.from)
+	if err != nil {
+		return err
+	}
+
+	points := make([]interface{}, 0, len(imp.batch))
+	for _, point := range imp.batch {
+		key := point.key()
+		if stored[key] {
+			imp.stats.Skipped++
+			continue
+		}
+
+		stored[key] = true
+		points = append(points, point)
+	}
+
+	if len(points) != 0 {
+		_, err = imp.server.Database().Database().Collection(Collection).InsertMany(
+			context.Background(),
+			points,
+			options.InsertMany().SetOrdered(false),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	imp.stats.Points += int64(len(points))
+	imp.batch = imp.batch[:0]
+	imp.uuids = make(map[string]bool)
+	imp.from = time.Time{}
+
+	return nil
+}
+
+/*
+add Buffer the price points of a single card, flushing the buffer once it is full
+*/
+func (imp *importer) add(uuid string, prices cardPrices) error {
+	imp.stats.Cards++
+
+	for gameAvailability, providers := range prices {
+		for provider, published := range providers {
+			for priceType, finishes := range map[string]map[string]map[string]float64{"retail": published.Retail, "buylist": published.Buylist} {
+				for finish, dates := range finishes {
+					meta := Meta{
+						Uuid:             uuid,
+						GameAvailability: gameAvailability,
+						Provider:         provider,
+						PriceType:        priceType,
+						Finish:           finish,
+						Currency:         published.Currency,
+					}
+
+					for day, value := range dates {
+						date, err := time.Parse(DateLayout, day)
+						if err != nil {
+							return fmt.Errorf("price: invalid date '%s' for card %s: %w", day, uuid, err)
+						}
+
+						if imp.from.IsZero() || date.Before(imp.from) {
+							imp.from = date
+						}
+
+						imp.uuids[uuid] = true
+						imp.batch = append(imp.batch, Point{Date: date, Meta: meta, Price: value})
+					}
+				}
+			}
+		}
+	}
+
+	if len(imp.batch) >= batchSize {
+		return imp.flush()
+	}
+
+	return nil
+}
+
+/*
+expectDelim Read the next token from the decoder, returning an error if it is not the expected delimiter
+*/
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("price: expected '%s' in price file, found '%v'", delim, token)
+	}
+
+	return nil
+}
+
+/*
+Import Stream an MTGJSON AllPrices or AllPricesToday file from reader into the price collection. Cards are
+decoded one at a time, so the file is never loaded into memory as a whole. Price points are skipped if a
+price for the same series and day is already stored, so that files with overlapping history can be imported
+repeatedly without storing duplicate points
+*/
+func Import(server *server.Server, reader io.Reader) (*ImportStats, error) {
+	err := EnsureCollection(server)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{server: server, batch: make([]Point, 0, batchSize), uuids: make(map[string]bool)}
+	decoder := json.NewDecoder(bufio.NewReader(reader))
+
+	err = expectDelim(decoder, '{')
+	if err != nil {
+		return nil, err
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if key != "data" {
+			var skipped json.RawMessage
+
+			err = decoder.Decode(&skipped)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		err = expectDelim(decoder, '{')
+		if err != nil {
+			return nil, err
+		}
+
+		for decoder.More() {
+			uuid, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			var prices cardPrices
+
+			err = decoder.Decode(&prices)
+			if err != nil {
+				return nil, err
+			}
+
+			err = imp.add(uuid.(string), prices)
+			if err != nil {
+				return nil, err
+			}
+
+			if imp.stats.Cards%10000 == 0 {
+				slog.Debug("Importing prices", "cards", imp.stats.Cards, "points", imp.stats.Points+int64(len(imp.batch)))
+			}
+		}
+
+		err = expectDelim(decoder, '}')
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = imp.flush()
+	if err != nil {
+		return nil, err
+	}
+
+	return &imp.stats, nil
+}
+
+/*
+ImportFile Import an MTGJSON price file from the local path. Files compressed with gzip (.gz) or bzip2
+(.bz2) are decompressed as they are read
+*/
+func ImportFile(server *server.Server, path string) (*ImportStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	case strings.HasSuffix(path, ".bz2"):
+		reader = bzip2.NewReader(file)
+	}
+
+	return Import(server, reader)
+}
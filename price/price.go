@@ -0,0 +1,284 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+	"time"
+)
+
+// Collection - The MongoDB time series collection that price points are stored in
+const Collection = "price"
+
+// DateLayout - The layout of the dates used by MTGJSON price files and the price endpoints
+const DateLayout = "2006-01-02"
+
+// ErrNoPrices - Returned when no price data is stored for a card
+var ErrNoPrices = errors.New("price: no price data found for card")
+
+/*
+Meta - Identifies the series that a price point belongs to. This is stored as the meta field of the time
+series collection, so each unique Meta is stored as its own bucket of points
+*/
+type Meta struct {
+	// Uuid - The UUID of the card the price is for
+	Uuid string `json:"uuid" bson:"uuid"`
+
+	// GameAvailability - Where the card is sold (ex. paper or mtgo)
+	GameAvailability string `json:"gameAvailability" bson:"gameAvailability"`
+
+	// Provider - The retailer that the price was pulled from (ex. tcgplayer or cardkingdom)
+	Provider string `json:"provider" bson:"provider"`
+
+	// PriceType - Whether the price is a retail or buylist price
+	PriceType string `json:"priceType" bson:"priceType"`
+
+	// Finish - The finish of the card the price is for (ex. normal, foil or etched)
+	Finish string `json:"finish" bson:"finish"`
+
+	// Currency - The currency of the price (ex. USD or EUR)
+	Currency string `json:"currency" bson:"currency"`
+}
+
+/*
+Point - The price of a card on a single day
+*/
+type Point struct {
+	// Date - The day that the price was recorded on
+	Date time.Time `json:"date" bson:"date"`
+
+	// Meta - The series that the price belongs to
+	Meta Meta `json:"meta" bson:"meta"`
+
+	// Price - The price of the card
+	Price float64 `json:"price" bson:"price"`
+}
+
+/*
+Filter - Narrows the price points returned for a card. Zero values are ignored
+*/
+type Filter struct {
+	// Uuid - The UUID of the card. This is required
+	Uuid string
+
+	// GameAvailability - Only return prices for paper or mtgo
+	GameAvailability string
+
+	// Provider - Only return prices from this provider
+	Provider string
+
+	// PriceType - Only return retail or buylist prices
+	PriceType string
+
+	// Finish - Only return prices for this finish
+	Finish string
+
+	// From - Only return prices recorded on or after this date
+	From time.Time
+
+	// To - Only return prices recorded on or before this date
+	To time.Time
+}
+
+/*
+query Build the MongoDB filter for this price filter
+*/
+func (filter Filter) query() bson.M {
+	ret := bson.M{"meta.uuid": filter.Uuid}
+
+	if filter.GameAvailability != "" {
+		ret["meta.gameAvailability"] = filter.GameAvailability
+	}
+
+	if filter.Provider != "" {
+		ret["meta.provider"] = filter.Provider
+	}
+
+	if filter.PriceType != "" {
+		ret["meta.priceType"] = filter.PriceType
+	}
+
+	if filter.Finish != "" {
+		ret["meta.finish"] = filter.Finish
+	}
+
+	date := bson.M{}
+	if !filter.From.IsZero() {
+		date["$gte"] = filter.From
+	}
+
+	if !filter.To.IsZero() {
+		date["$lte"] = filter.To
+	}
+
+	if len(date) != 0 {
+		ret["date"] = date
+	}
+
+	return ret
+}
+
+/*
+EnsureCollection Create the time series collection that prices are stored in, along with an index on the
+card UUID, if it does not already exist
+*/
+func EnsureCollection(server *server.Server) error {
+	ctx := context.Background()
+	db := server.Database().Database()
+
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": Collection})
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		err = db.CreateCollection(
+			ctx,
+			Collection,
+			options.CreateCollection().SetTimeSeriesOptions(
+				options.TimeSeries().SetTimeField("date").SetMetaField("meta").SetGranularity("hours"),
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Collection(Collection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "meta.uuid", Value: 1}, {Key: "date", Value: -1}},
+			Options: options.Index().SetName("price_uuid_date"),
+		},
+	})
+
+	return err
+}
+
+/*
+key Return a key identifying the series and day of a price point. Two points with the same key are
+duplicates of each other
+*/
+func (point Point) key() string {
+	meta := point.Meta
+	return strings.Join([]string{
+		meta.Uuid,
+		meta.GameAvailability,
+		meta.Provider,
+		meta.PriceType,
+		meta.Finish,
+		meta.Currency,
+		point.Date.Format(DateLayout),
+	}, "|")
+}
+
+/*
+StoredKeys Return the keys of every price point stored for any of the passed cards on or after the passed
+date. Time series collections do not support unique indexes, so this is used to skip points that are already
+stored
+*/
+func StoredKeys(server *server.Server, uuids []string, from time.Time) (map[string]bool, error) {
+	ctx := context.Background()
+
+	cursor, err := server.Database().Database().Collection(Collection).Find(
+		ctx,
+		bson.M{"meta.uuid": bson.M{"$in": uuids}, "date": bson.M{"$gte": from}},
+		options.Find().SetProjection(bson.M{"_id": 0, "date": 1, "meta": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var points []Point
+
+	err = cursor.All(ctx, &points)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]bool, len(points))
+	for _, point := range points {
+		ret[point.key()] = true
+	}
+
+	return ret, nil
+}
+
+/*
+Current Fetch the most recent price of each series matching the filter. Returns ErrNoPrices if no prices
+match the filter
+*/
+func Current(server *server.Server, filter Filter) ([]*Point, error) {
+	ctx := context.Background()
+
+	pipeline := bson.A{
+		bson.M{"$match": filter.query()},
+		bson.M{"$sort": bson.D{{Key: "date", Value: -1}}},
+		bson.M{"$group": bson.M{
+			"_id":   "$meta",
+			"date":  bson.M{"$first": "$date"},
+			"price": bson.M{"$first": "$price"},
+		}},
+		bson.M{"$project": bson.M{"_id": 0, "meta": "$_id", "date": 1, "price": 1}},
+		bson.M{"$sort": bson.D{
+			{Key: "meta.gameAvailability", Value: 1},
+			{Key: "meta.provider", Value: 1},
+			{Key: "meta.priceType", Value: 1},
+			{Key: "meta.finish", Value: 1},
+		}},
+	}
+
+	cursor, err := server.Database().Database().Collection(Collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var points []*Point
+
+	err = cursor.All(ctx, &points)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) == 0 {
+		return nil, ErrNoPrices
+	}
+
+	return points, nil
+}
+
+/*
+History Fetch every price point matching the filter, sorted by date. Returns ErrNoPrices if no prices match
+the filter
+*/
+func History(server *server.Server, filter Filter) ([]*Point, error) {
+	ctx := context.Background()
+
+	cursor, err := server.Database().Database().Collection(Collection).Find(
+		ctx,
+		filter.query(),
+		options.Find().SetSort(bson.D{{Key: "date", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var points []*Point
+
+	err = cursor.All(ctx, &points)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) == 0 {
+		return nil, ErrNoPrices
+	}
+
+	return points, nil
+}
@@ -0,0 +1,278 @@
+package price
+
+import (
+	"context"
+	cardModel "github.com/stevezaluk/mtgjson-models/card"
+	deckModel "github.com/stevezaluk/mtgjson-models/deck"
+	"github.com/stevezaluk/mtgjson-sdk/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"math"
+)
+
+/*
+defaultProviders - The provider used to value a deck for each game availability, if one is not requested
+*/
+var defaultProviders = map[string]string{
+	"paper": "tcgplayer",
+	"mtgo":  "cardhoarder",
+}
+
+/*
+ValuationOptions - Selects which of the stored prices are used to value a deck
+*/
+type ValuationOptions struct {
+	// GameAvailability - Value the deck using paper or mtgo prices. Defaults to paper
+	GameAvailability string
+
+	// Provider - The provider to pull prices from. Defaults to tcgplayer for paper, and cardhoarder for mtgo
+	Provider string
+
+	// PriceType - Value the deck using retail or buylist prices. Defaults to retail
+	PriceType string
+
+	// Finish - If set, every card is valued using this finish. Otherwise foil cards are valued using the
+	// foil price, and all other cards are valued using the normal price
+	Finish string
+}
+
+/*
+CardValue - The value of a single entry in a deck
+*/
+type CardValue struct {
+	// Uuid - The UUID of the card
+	Uuid string `json:"uuid"`
+
+	// Name - The name of the card
+	Name string `json:"name"`
+
+	// Count - The amount of copies of the card in the board
+	Count int64 `json:"count"`
+
+	// Finish - The finish that the card was valued using
+	Finish string `json:"finish"`
+
+	// UnitPrice - The price of a single copy of the card
+	UnitPrice float64 `json:"unitPrice"`
+
+	// Subtotal - The price of every copy of the card
+	Subtotal float64 `json:"subtotal"`
+}
+
+/*
+BoardValue - The value of a single board of a deck
+*/
+type BoardValue struct {
+	// Total - The combined value of every priced card in the board
+	Total float64 `json:"total"`
+
+	// Cards - The value of each priced card in the board
+	Cards []*CardValue `json:"cards"`
+}
+
+/*
+MissingPrice - A card in a deck that no price was stored for
+*/
+type MissingPrice struct {
+	// Uuid - The UUID of the card
+	Uuid string `json:"uuid"`
+
+	// Name - The name of the card
+	Name string `json:"name"`
+
+	// Board - The board the card is in
+	Board string `json:"board"`
+
+	// Finish - The finish that a price was looked up for
+	Finish string `json:"finish"`
+}
+
+/*
+Valuation - The value of a deck, broken down by board and by card
+*/
+type Valuation struct {
+	// GameAvailability - Whether the deck was valued using paper or mtgo prices
+	GameAvailability string `json:"gameAvailability"`
+
+	// Provider - The provider that prices were pulled from
+	Provider string `json:"provider"`
+
+	// PriceType - Whether the deck was valued using retail or buylist prices
+	PriceType string `json:"priceType"`
+
+	// Currency - The currency of the prices. This is empty if no card in the deck was priced
+	Currency string `json:"currency"`
+
+	// Total - The combined value of every priced card in the deck
+	Total float64 `json:"total"`
+
+	// Boards - The value of each board in the deck, keyed by the board name
+	Boards map[string]*BoardValue `json:"boards"`
+
+	// Missing - The cards that could not be valued, as no price was stored for them
+	Missing []*MissingPrice `json:"missing"`
+}
+
+/*
+roundCents Round a price to two decimal places
+*/
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+/*
+CardUuid Return the UUID that prices are stored under for a card in a deck. The mtgjsonV4Id is used if the
+card does not have a UUID
+*/
+func CardUuid(card *cardModel.CardDeck) string {
+	if card.Uuid == "" && card.Identifiers != nil {
+		return card.Identifiers.MtgjsonV4Id
+	}
+
+	return card.Uuid
+}
+
+/*
+latestByCard Fetch the most recent price of each finish for each card, using the game availability, provider
+and price type in filter. The returned map is keyed by card UUID and then by finish
+*/
+func latestByCard(server *server.Server, uuids []string, filter Filter) (map[string]map[string]*Point, error) {
+	ctx := context.Background()
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"meta.uuid":             bson.M{"$in": uuids},
+			"meta.gameAvailability": filter.GameAvailability,
+			"meta.provider":         filter.Provider,
+			"meta.priceType":        filter.PriceType,
+		}},
+		bson.M{"$sort": bson.D{{Key: "date", Value: -1}}},
+		bson.M{"$group": bson.M{
+			"_id":   "$meta",
+			"date":  bson.M{"$first": "$date"},
+			"price": bson.M{"$first": "$price"},
+		}},
+		bson.M{"$project": bson.M{"_id": 0, "meta": "$_id", "date": 1, "price": 1}},
+	}
+
+	cursor, err := server.Database().Database().Collection(Collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var points []*Point
+
+	err = cursor.All(ctx, &points)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]map[string]*Point)
+	for _, point := range points {
+		if ret[point.Meta.Uuid] == nil {
+			ret[point.Meta.Uuid] = make(map[string]*Point)
+		}
+
+		ret[point.Meta.Uuid][point.Meta.Finish] = point
+	}
+
+	return ret, nil
+}
+
+/*
+ValueDeck Value the contents of a deck using the most recent stored price of each card. Each card's price
+is multiplied by its count, and totalled for each board and for the deck as a whole. Cards without a stored
+price are listed in Missing rather than counted as zero
+*/
+func ValueDeck(server *server.Server, contents *deckModel.DeckContents, opts ValuationOptions) (*Valuation, error) {
+	if opts.GameAvailability == "" {
+		opts.GameAvailability = "paper"
+	}
+
+	if opts.Provider == "" {
+		opts.Provider = defaultProviders[opts.GameAvailability]
+	}
+
+	if opts.PriceType == "" {
+		opts.PriceType = "retail"
+	}
+
+	boards := []struct {
+		name  string
+		cards []*cardModel.CardDeck
+	}{
+		{"mainBoard", contents.MainBoard},
+		{"sideBoard", contents.SideBoard},
+		{"commander", contents.Commander},
+	}
+
+	var uuids []string
+	for _, board := range boards {
+		for _, card := range board.cards {
+			uuids = append(uuids, CardUuid(card))
+		}
+	}
+
+	prices, err := latestByCard(server, uuids, Filter{
+		GameAvailability: opts.GameAvailability,
+		Provider:         opts.Provider,
+		PriceType:        opts.PriceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := &Valuation{
+		GameAvailability: opts.GameAvailability,
+		Provider:         opts.Provider,
+		PriceType:        opts.PriceType,
+		Boards:           make(map[string]*BoardValue),
+		Missing:          []*MissingPrice{},
+	}
+
+	for _, board := range boards {
+		value := &BoardValue{Cards: []*CardValue{}}
+		valuation.Boards[board.name] = value
+
+		for _, card := range board.cards {
+			uuid := CardUuid(card)
+
+			finish := opts.Finish
+			if finish == "" {
+				finish = "normal"
+				if card.IsFoil {
+					finish = "foil"
+				}
+			}
+
+			point, ok := prices[uuid][finish]
+			if !ok {
+				valuation.Missing = append(valuation.Missing, &MissingPrice{Uuid: uuid, Name: card.Name, Board: board.name, Finish: finish})
+				continue
+			}
+
+			if valuation.Currency == "" {
+				valuation.Currency = point.Meta.Currency
+			}
+
+			subtotal := point.Price * float64(card.Count)
+			value.Cards = append(value.Cards, &CardValue{
+				Uuid:      uuid,
+				Name:      card.Name,
+				Count:     card.Count,
+				Finish:    finish,
+				UnitPrice: point.Price,
+				Subtotal:  roundCents(subtotal),
+			})
+
+			value.Total += subtotal
+		}
+
+		valuation.Total += value.Total
+		value.Total = roundCents(value.Total)
+	}
+
+	valuation.Total = roundCents(valuation.Total)
+
+	return valuation, nil
+}
@@ -0,0 +1,105 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+Node - A node of a parsed query. Position is the position in the query that the node begins at,
+counted in runes
+*/
+type Node interface {
+	Position() int
+	String() string
+}
+
+/*
+AndNode - Matches cards that match every one of its children
+*/
+type AndNode struct {
+	Children []Node
+	Pos      int
+}
+
+func (node *AndNode) Position() int { return node.Pos }
+
+func (node *AndNode) String() string {
+	parts := make([]string, len(node.Children))
+	for i, child := range node.Children {
+		parts[i] = child.String()
+	}
+
+	return "(" + strings.Join(parts, " and ") + ")"
+}
+
+/*
+OrNode - Matches cards that match at least one of its children
+*/
+type OrNode struct {
+	Children []Node
+	Pos      int
+}
+
+func (node *OrNode) Position() int { return node.Pos }
+
+func (node *OrNode) String() string {
+	parts := make([]string, len(node.Children))
+	for i, child := range node.Children {
+		parts[i] = child.String()
+	}
+
+	return "(" + strings.Join(parts, " or ") + ")"
+}
+
+/*
+NotNode - Matches cards that do not match its child
+*/
+type NotNode struct {
+	Child Node
+	Pos   int
+}
+
+func (node *NotNode) Position() int { return node.Pos }
+
+func (node *NotNode) String() string {
+	return "-" + node.Child.String()
+}
+
+/*
+ComparisonNode - Compares an attribute of a card against a value (ex. mv<=3). Key is always lower case,
+and ValuePos is the position that the value begins at
+*/
+type ComparisonNode struct {
+	Key      string
+	Op       string
+	Value    string
+	Pos      int
+	ValuePos int
+}
+
+func (node *ComparisonNode) Position() int { return node.Pos }
+
+func (node *ComparisonNode) String() string {
+	return node.Key + node.Op + strconv.Quote(node.Value)
+}
+
+/*
+NameNode - Matches cards by name. A bare word matches cards whose name contains it, and an Exact name
+(ex. !"Lightning Bolt") matches cards with exactly that name, ignoring case
+*/
+type NameNode struct {
+	Value string
+	Exact bool
+	Pos   int
+}
+
+func (node *NameNode) Position() int { return node.Pos }
+
+func (node *NameNode) String() string {
+	if node.Exact {
+		return "!" + strconv.Quote(node.Value)
+	}
+
+	return strconv.Quote(node.Value)
+}
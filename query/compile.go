@@ -0,0 +1,491 @@
+package query
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+colorNames - Maps the color names accepted in color and color identity comparisons to their color
+*/
+var colorNames = map[string]string{
+	"white": "W",
+	"blue":  "U",
+	"black": "B",
+	"red":   "R",
+	"green": "G",
+}
+
+/*
+rarities - The rarities that a card can have, in the order used by rarity comparisons
+*/
+var rarities = []string{"common", "uncommon", "rare", "special", "mythic", "bonus"}
+
+/*
+rarityAbbreviations - Maps the single letter rarity abbreviations to their rarity
+*/
+var rarityAbbreviations = map[string]string{"c": "common", "u": "uncommon", "r": "rare", "s": "special", "m": "mythic", "b": "bonus"}
+
+/*
+isFlags - Maps the values accepted by is: and not: to the boolean card field that they test
+*/
+var isFlags = map[string]string{
+	"reprint":    "isReprint",
+	"promo":      "isPromo",
+	"reserved":   "isReserved",
+	"fullart":    "isFullArt",
+	"funny":      "isFunny",
+	"textless":   "isTextless",
+	"oversized":  "isOversized",
+	"onlineonly": "isOnlineOnly",
+	"digital":    "isOnlineOnly",
+}
+
+/*
+formats - The formats that a card's legalities are recorded for
+*/
+var formats = []string{
+	"alchemy", "brawl", "commander", "duel", "explorer", "future", "gladiator", "historic", "historicbrawl",
+	"legacy", "modern", "oathbreaker", "oldschool", "pauper", "paupercommander", "penny", "pioneer", "predh",
+	"premodern", "standard", "standardbrawl", "timeless", "vintage",
+}
+
+/*
+numericOps - Maps the comparison operators to their MongoDB equivalents. The ':' operator is treated as
+equality
+*/
+var numericOps = map[string]string{":": "$eq", "=": "$eq", "!=": "$ne", "<": "$lt", "<=": "$lte", ">": "$gt", ">=": "$gte"}
+
+/*
+compiler - Compiles a single comparison into a MongoDB filter
+*/
+type compiler func(node *ComparisonNode) (bson.M, error)
+
+/*
+keywords - Maps each keyword accepted in a comparison to its compiler. Aliases map to the same compiler
+*/
+var keywords = map[string]compiler{
+	"name":       textCompiler("name"),
+	"t":          textCompiler("type"),
+	"type":       textCompiler("type"),
+	"o":          textCompiler("text"),
+	"oracle":     textCompiler("text"),
+	"a":          textCompiler("artist"),
+	"artist":     textCompiler("artist"),
+	"c":          colorCompiler("colors", ">="),
+	"color":      colorCompiler("colors", ">="),
+	"id":         colorCompiler("colorIdentity", "<="),
+	"identity":   colorCompiler("colorIdentity", "<="),
+	"ci":         colorCompiler("colorIdentity", "<="),
+	"mv":         compileManaValue,
+	"cmc":        compileManaValue,
+	"manavalue":  compileManaValue,
+	"pow":        statCompiler("power"),
+	"power":      statCompiler("power"),
+	"tou":        statCompiler("toughness"),
+	"toughness":  statCompiler("toughness"),
+	"r":          compileRarity,
+	"rarity":     compileRarity,
+	"s":          exactCompiler("setCode", strings.ToUpper),
+	"e":          exactCompiler("setCode", strings.ToUpper),
+	"set":        exactCompiler("setCode", strings.ToUpper),
+	"edition":    exactCompiler("setCode", strings.ToUpper),
+	"cn":         exactCompiler("number", nil),
+	"number":     exactCompiler("number", nil),
+	"frame":      exactCompiler("frameVersion", nil),
+	"k":          compileKeyword,
+	"keyword":    compileKeyword,
+	"is":         flagCompiler(true),
+	"not":        flagCompiler(false),
+	"f":          legalityCompiler("Legal"),
+	"format":     legalityCompiler("Legal"),
+	"legal":      legalityCompiler("Legal"),
+	"banned":     legalityCompiler("Banned"),
+	"restricted": legalityCompiler("Restricted"),
+}
+
+/*
+valueError Return a SyntaxError pointing at the value of a comparison
+*/
+func valueError(node *ComparisonNode, message string) error {
+	return &SyntaxError{Position: node.ValuePos, Token: node.Value, Message: message}
+}
+
+/*
+opError Return a SyntaxError for a comparison whose operator is not supported by its keyword
+*/
+func opError(node *ComparisonNode) error {
+	return &SyntaxError{
+		Position: node.Pos,
+		Token:    node.Key + node.Op + node.Value,
+		Message:  "operator '" + node.Op + "' is not supported for '" + node.Key + "'",
+	}
+}
+
+/*
+textCompiler Return a compiler for a text field. The ':' operator matches values containing the value,
+'=' matches the value exactly, and '!=' matches anything else. Each ignores case
+*/
+func textCompiler(field string) compiler {
+	return func(node *ComparisonNode) (bson.M, error) {
+		quoted := regexp.QuoteMeta(node.Value)
+
+		switch node.Op {
+		case ":":
+			return bson.M{field: bson.M{"$regex": quoted, "$options": "i"}}, nil
+		case "=":
+			return bson.M{field: bson.M{"$regex": "^" + quoted + "$", "$options": "i"}}, nil
+		case "!=":
+			return bson.M{field: bson.M{"$not": bson.M{"$regex": "^" + quoted + "$", "$options": "i"}}}, nil
+		}
+
+		return nil, opError(node)
+	}
+}
+
+/*
+exactCompiler Return a compiler for a field that only supports equality. If normalize is not nil, then
+the value is passed through it first
+*/
+func exactCompiler(field string, normalize func(string) string) compiler {
+	return func(node *ComparisonNode) (bson.M, error) {
+		value := node.Value
+		if normalize != nil {
+			value = normalize(value)
+		}
+
+		switch node.Op {
+		case ":", "=":
+			return bson.M{field: value}, nil
+		case "!=":
+			return bson.M{field: bson.M{"$ne": value}}, nil
+		}
+
+		return nil, opError(node)
+	}
+}
+
+/*
+parseColors Parse a color value into its colors. Values are either a string of color letters (ex. 'rg'),
+a color name (ex. 'red'), or 'c'/'colorless'. The 'm'/'multicolor' value is reported separately, as it
+does not describe a set of colors
+*/
+func parseColors(value string) (colors []string, multicolor bool, ok bool) {
+	value = strings.ToLower(value)
+
+	switch value {
+	case "c", "colorless":
+		return []string{}, false, true
+	case "m", "multicolor":
+		return nil, true, true
+	}
+
+	if color, found := colorNames[value]; found {
+		return []string{color}, false, true
+	}
+
+	colors = []string{}
+	for _, r := range strings.ToUpper(value) {
+		if !strings.ContainsRune("WUBRG", r) {
+			return nil, false, false
+		}
+
+		if !slices.Contains(colors, string(r)) {
+			colors = append(colors, string(r))
+		}
+	}
+
+	return colors, false, true
+}
+
+/*
+colorCompiler Return a compiler for a color field. The ':' operator behaves like defaultOp, which is '>='
+(has at least these colors) for colors and '<=' (within these colors) for color identity. Comparing
+with ':' against colorless always matches colorless cards exactly
+*/
+func colorCompiler(field string, defaultOp string) compiler {
+	return func(node *ComparisonNode) (bson.M, error) {
+		colors, multicolor, ok := parseColors(node.Value)
+		if !ok {
+			return nil, valueError(node, "invalid color, expected a combination of w, u, b, r and g, or a color name")
+		}
+
+		op := node.Op
+		if op == ":" {
+			op = defaultOp
+			if len(colors) == 0 {
+				op = "="
+			}
+		}
+
+		if multicolor {
+			switch op {
+			case "=", ">=", "<=":
+				return bson.M{field + ".1": bson.M{"$exists": true}}, nil
+			case "!=":
+				return bson.M{field + ".1": bson.M{"$exists": false}}, nil
+			}
+
+			return nil, opError(node)
+		}
+
+		count := len(colors)
+		exact := bson.M{field: bson.M{"$size": count}}
+		if count != 0 {
+			exact = bson.M{field: bson.M{"$all": colors, "$size": count}}
+		}
+		within := bson.M{field: bson.M{"$not": bson.M{"$elemMatch": bson.M{"$nin": colors}}}}
+
+		switch op {
+		case "=":
+			return exact, nil
+		case "!=":
+			return bson.M{"$nor": bson.A{exact}}, nil
+		case ">=":
+			if count == 0 {
+				return bson.M{}, nil
+			}
+			return bson.M{field: bson.M{"$all": colors}}, nil
+		case ">":
+			more := bson.M{field + "." + strconv.Itoa(count): bson.M{"$exists": true}}
+			if count == 0 {
+				return more, nil
+			}
+			return bson.M{"$and": bson.A{bson.M{field: bson.M{"$all": colors}}, more}}, nil
+		case "<=":
+			return within, nil
+		case "<":
+			if count == 0 {
+				return nil, valueError(node, "no card has fewer than zero colors")
+			}
+			return bson.M{"$and": bson.A{within, bson.M{field + "." + strconv.Itoa(count-1): bson.M{"$exists": false}}}}, nil
+		}
+
+		return nil, opError(node)
+	}
+}
+
+/*
+compileManaValue Compile a numeric comparison against the mana value of a card
+*/
+func compileManaValue(node *ComparisonNode) (bson.M, error) {
+	value, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil {
+		return nil, valueError(node, "mana value must be a number")
+	}
+
+	return bson.M{"manaValue": bson.M{numericOps[node.Op]: value}}, nil
+}
+
+/*
+statCompiler Return a compiler for power or toughness. These are stored as strings, as they may not be
+numbers (ex. '*'), so equality compares the raw value and ordering converts the stored value to a number,
+ignoring cards where it is not one
+*/
+func statCompiler(field string) compiler {
+	return func(node *ComparisonNode) (bson.M, error) {
+		switch node.Op {
+		case ":", "=":
+			return bson.M{field: node.Value}, nil
+		case "!=":
+			return bson.M{field: bson.M{"$ne": node.Value}}, nil
+		}
+
+		value, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return nil, valueError(node, field+" must be a number when compared with '"+node.Op+"'")
+		}
+
+		converted := bson.M{"$convert": bson.M{"input": "$" + field, "to": "double", "onError": nil, "onNull": nil}}
+		return bson.M{"$expr": bson.M{"$and": bson.A{
+			bson.M{"$ne": bson.A{converted, nil}},
+			bson.M{numericOps[node.Op]: bson.A{converted, value}},
+		}}}, nil
+	}
+}
+
+/*
+compileRarity Compile a comparison against the rarity of a card. Rarities are ordered from common to
+bonus, so 'r>=rare' matches rare, special, mythic and bonus cards
+*/
+func compileRarity(node *ComparisonNode) (bson.M, error) {
+	value := strings.ToLower(node.Value)
+	if full, ok := rarityAbbreviations[value]; ok {
+		value = full
+	}
+
+	rank := slices.Index(rarities, value)
+	if rank == -1 {
+		return nil, valueError(node, "invalid rarity, expected one of "+strings.Join(rarities, ", "))
+	}
+
+	matched := []string{}
+	for i, rarity := range rarities {
+		var match bool
+		switch node.Op {
+		case ":", "=":
+			match = i == rank
+		case "!=":
+			match = i != rank
+		case "<":
+			match = i < rank
+		case "<=":
+			match = i <= rank
+		case ">":
+			match = i > rank
+		case ">=":
+			match = i >= rank
+		}
+
+		if match {
+			matched = append(matched, rarity)
+		}
+	}
+
+	return bson.M{"rarity": bson.M{"$in": matched}}, nil
+}
+
+/*
+compileKeyword Compile a comparison against the keyword abilities of a card, ignoring case
+*/
+func compileKeyword(node *ComparisonNode) (bson.M, error) {
+	pattern := bson.M{"$regex": "^" + regexp.QuoteMeta(node.Value) + "$", "$options": "i"}
+
+	switch node.Op {
+	case ":", "=":
+		return bson.M{"keywords": pattern}, nil
+	case "!=":
+		return bson.M{"keywords": bson.M{"$not": pattern}}, nil
+	}
+
+	return nil, opError(node)
+}
+
+/*
+flagCompiler Return a compiler for is: and not:, which test one of the boolean fields in isFlags. The
+foil and nonfoil values test the finishes of the card instead
+*/
+func flagCompiler(want bool) compiler {
+	return func(node *ComparisonNode) (bson.M, error) {
+		if node.Op != ":" && node.Op != "=" {
+			return nil, opError(node)
+		}
+
+		var filter bson.M
+
+		value := strings.ToLower(node.Value)
+		switch value {
+		case "foil", "nonfoil", "etched":
+			filter = bson.M{"finishes": value}
+		default:
+			field, ok := isFlags[value]
+			if !ok {
+				names := []string{"foil", "nonfoil", "etched"}
+				for name := range isFlags {
+					names = append(names, name)
+				}
+				slices.Sort(names)
+
+				return nil, valueError(node, "unknown value, expected one of "+strings.Join(names, ", "))
+			}
+
+			filter = bson.M{field: true}
+		}
+
+		if !want {
+			return bson.M{"$nor": bson.A{filter}}, nil
+		}
+
+		return filter, nil
+	}
+}
+
+/*
+legalityCompiler Return a compiler that matches cards whose legality in the format passed as the value
+is status (ex. 'Legal' or 'Banned')
+*/
+func legalityCompiler(status string) compiler {
+	return func(node *ComparisonNode) (bson.M, error) {
+		format := strings.ToLower(node.Value)
+		if !slices.Contains(formats, format) {
+			return nil, valueError(node, "unknown format, expected one of "+strings.Join(formats, ", "))
+		}
+
+		switch node.Op {
+		case ":", "=":
+			return bson.M{"legalities." + format: status}, nil
+		case "!=":
+			return bson.M{"legalities." + format: bson.M{"$ne": status}}, nil
+		}
+
+		return nil, opError(node)
+	}
+}
+
+/*
+compileAll Compile each of the children and join them with a MongoDB logical operator
+*/
+func compileAll(operator string, children []Node) (bson.M, error) {
+	clauses := bson.A{}
+	for _, child := range children {
+		clause, err := Compile(child)
+		if err != nil {
+			return nil, err
+		}
+
+		clauses = append(clauses, clause)
+	}
+
+	return bson.M{operator: clauses}, nil
+}
+
+/*
+Compile Compile a parsed query into a MongoDB filter. A *SyntaxError is returned if the query uses an
+unknown keyword, or a value or operator that its keyword does not support
+*/
+func Compile(node Node) (bson.M, error) {
+	switch n := node.(type) {
+	case *AndNode:
+		return compileAll("$and", n.Children)
+	case *OrNode:
+		return compileAll("$or", n.Children)
+	case *NotNode:
+		clause, err := Compile(n.Child)
+		if err != nil {
+			return nil, err
+		}
+
+		return bson.M{"$nor": bson.A{clause}}, nil
+	case *NameNode:
+		pattern := regexp.QuoteMeta(n.Value)
+		if n.Exact {
+			pattern = "^" + pattern + "$"
+		}
+
+		return bson.M{"name": bson.M{"$regex": pattern, "$options": "i"}}, nil
+	case *ComparisonNode:
+		compile, ok := keywords[n.Key]
+		if !ok {
+			return nil, &SyntaxError{Position: n.Pos, Token: n.Key, Message: "unknown keyword '" + n.Key + "'"}
+		}
+
+		return compile(n)
+	}
+
+	return nil, &SyntaxError{Position: node.Position(), Token: node.String(), Message: "unsupported query node"}
+}
+
+/*
+Filter Parse a query and compile it into a MongoDB filter
+*/
+func Filter(input string) (bson.M, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(node)
+}
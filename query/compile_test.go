@@ -0,0 +1,100 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bson.M
+	}{
+		{"bolt", bson.M{"name": bson.M{"$regex": "bolt", "$options": "i"}}},
+		{`!"Lightning Bolt"`, bson.M{"name": bson.M{"$regex": "^Lightning Bolt$", "$options": "i"}}},
+		{"t:goblin", bson.M{"type": bson.M{"$regex": "goblin", "$options": "i"}}},
+		{"t=goblin", bson.M{"type": bson.M{"$regex": "^goblin$", "$options": "i"}}},
+		{"mv>=3", bson.M{"manaValue": bson.M{"$gte": 3.0}}},
+		{"cmc:2", bson.M{"manaValue": bson.M{"$eq": 2.0}}},
+		{"pow=*", bson.M{"power": "*"}},
+		{"r>=mythic", bson.M{"rarity": bson.M{"$in": []string{"mythic", "bonus"}}}},
+		{"r<u", bson.M{"rarity": bson.M{"$in": []string{"common"}}}},
+		{"c:rg", bson.M{"colors": bson.M{"$all": []string{"R", "G"}}}},
+		{"c:red", bson.M{"colors": bson.M{"$all": []string{"R"}}}},
+		{"c=rr", bson.M{"colors": bson.M{"$all": []string{"R"}, "$size": 1}}},
+		{"c:m", bson.M{"colors.1": bson.M{"$exists": true}}},
+		{"id:c", bson.M{"colorIdentity": bson.M{"$size": 0}}},
+		{"id:wu", bson.M{"colorIdentity": bson.M{"$not": bson.M{"$elemMatch": bson.M{"$nin": []string{"W", "U"}}}}}},
+		{"s:m21", bson.M{"setCode": "M21"}},
+		{"cn!=12", bson.M{"number": bson.M{"$ne": "12"}}},
+		{"k:flying", bson.M{"keywords": bson.M{"$regex": "^flying$", "$options": "i"}}},
+		{"is:foil", bson.M{"finishes": "foil"}},
+		{"not:etched", bson.M{"$nor": bson.A{bson.M{"finishes": "etched"}}}},
+		{"f:Modern", bson.M{"legalities.modern": "Legal"}},
+		{"banned:legacy", bson.M{"legalities.legacy": "Banned"}},
+		{"-t:land", bson.M{"$nor": bson.A{bson.M{"type": bson.M{"$regex": "land", "$options": "i"}}}}},
+		{"bolt or shock", bson.M{"$or": bson.A{
+			bson.M{"name": bson.M{"$regex": "bolt", "$options": "i"}},
+			bson.M{"name": bson.M{"$regex": "shock", "$options": "i"}},
+		}}},
+		{"a.b mv<1", bson.M{"$and": bson.A{
+			bson.M{"name": bson.M{"$regex": `a\.b`, "$options": "i"}},
+			bson.M{"manaValue": bson.M{"$lt": 1.0}},
+		}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := Filter(test.input)
+			if err != nil {
+				t.Fatalf("Filter(%q) returned an error: %v", test.input, err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Filter(%q) = %v, want %v", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFilterSyntaxError(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+		token    string
+	}{
+		{"foo:bar", 0, "foo"},
+		{"bolt foo:bar", 5, "foo"},
+		{"mv<=x", 4, "x"},
+		{`"Æther" mv<=x`, 12, "x"},
+		{"t>creature", 0, "t>creature"},
+		{"r:legendary", 2, "legendary"},
+		{"c:purple", 2, "purple"},
+		{"c<c", 2, "c"},
+		{"f:casual", 2, "casual"},
+		{"is:shiny", 3, "shiny"},
+		{"pow>x", 4, "x"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := Filter(test.input)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Filter(%q) returned %v, want a *SyntaxError", test.input, err)
+			}
+
+			if syntaxErr.Position != test.position {
+				t.Errorf("Position = %d, want %d", syntaxErr.Position, test.position)
+			}
+
+			if syntaxErr.Token != test.token {
+				t.Errorf("Token = %q, want %q", syntaxErr.Token, test.token)
+			}
+		})
+	}
+}
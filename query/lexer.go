@@ -0,0 +1,205 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+tokenKind - The kind of a token produced by the lexer
+*/
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenMinus
+	tokenBang
+	tokenOr
+	tokenAnd
+	tokenWord
+	tokenString
+	tokenComparison
+)
+
+/*
+token - A single token of a query. For comparisons, Key, Op and Value are split out of the raw text, and
+ValuePosition is the position that the value begins at
+*/
+type token struct {
+	kind          tokenKind
+	text          string
+	position      int
+	key           string
+	op            string
+	value         string
+	valuePosition int
+}
+
+/*
+operators - The comparison operators accepted between a keyword and its value. Two character operators
+are listed first so that they are matched before their one character prefixes
+*/
+var operators = []string{"<=", ">=", "!=", ":", "=", "<", ">"}
+
+/*
+isKeyRune Return true if r can be part of a keyword (ex. the 'mv' in 'mv<=3')
+*/
+func isKeyRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+/*
+isBreakRune Return true if r ends an unquoted word
+*/
+func isBreakRune(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')'
+}
+
+/*
+lexer - Splits a query into tokens. Positions are counted in runes from the start of the query
+*/
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+/*
+readQuoted Read a double quoted string beginning at the current position, returning its unquoted value.
+A backslash escapes the character following it
+*/
+func (l *lexer) readQuoted() (string, error) {
+	start := l.pos
+	l.pos++ // opening quote
+
+	var value strings.Builder
+	for l.pos < len(l.input) {
+		r := l.input[l.pos]
+		switch {
+		case r == '\\' && l.pos+1 < len(l.input):
+			value.WriteRune(l.input[l.pos+1])
+			l.pos += 2
+		case r == '"':
+			l.pos++
+			return value.String(), nil
+		default:
+			value.WriteRune(r)
+			l.pos++
+		}
+	}
+
+	return "", &SyntaxError{Position: start, Token: string(l.input[start:]), Message: "unterminated quoted string"}
+}
+
+/*
+readWord Read an unquoted word beginning at the current position
+*/
+func (l *lexer) readWord() string {
+	start := l.pos
+	for l.pos < len(l.input) && !isBreakRune(l.input[l.pos]) {
+		l.pos++
+	}
+
+	return string(l.input[start:l.pos])
+}
+
+/*
+matchOperator Return the comparison operator beginning at the current position, or an empty string if
+there is not one
+*/
+func (l *lexer) matchOperator() string {
+	rest := string(l.input[l.pos:min(l.pos+2, len(l.input))])
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+/*
+next Return the next token of the query
+*/
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, position: l.pos}, nil
+	}
+
+	start := l.pos
+	switch l.input[l.pos] {
+	case '(':
+		l.pos++
+		return token{kind: tokenLParen, text: "(", position: start}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokenRParen, text: ")", position: start}, nil
+	case '-':
+		l.pos++
+		return token{kind: tokenMinus, text: "-", position: start}, nil
+	case '!':
+		l.pos++
+		return token{kind: tokenBang, text: "!", position: start}, nil
+	case '"':
+		value, err := l.readQuoted()
+		if err != nil {
+			return token{}, err
+		}
+
+		return token{kind: tokenString, text: string(l.input[start:l.pos]), position: start, value: value}, nil
+	}
+
+	for l.pos < len(l.input) && isKeyRune(l.input[l.pos]) {
+		l.pos++
+	}
+
+	if l.pos > start {
+		if op := l.matchOperator(); op != "" {
+			key := string(l.input[start:l.pos])
+			l.pos += len([]rune(op))
+
+			valuePosition := l.pos
+			var value string
+			if l.pos < len(l.input) && l.input[l.pos] == '"' {
+				quoted, err := l.readQuoted()
+				if err != nil {
+					return token{}, err
+				}
+				value = quoted
+			} else {
+				value = l.readWord()
+			}
+
+			if l.pos == valuePosition {
+				return token{}, &SyntaxError{Position: start, Token: string(l.input[start:l.pos]), Message: "expected a value after '" + op + "'"}
+			}
+
+			return token{
+				kind:          tokenComparison,
+				text:          string(l.input[start:l.pos]),
+				position:      start,
+				key:           strings.ToLower(key),
+				op:            op,
+				value:         value,
+				valuePosition: valuePosition,
+			}, nil
+		}
+	}
+
+	l.pos = start
+	word := l.readWord()
+
+	switch strings.ToLower(word) {
+	case "or":
+		return token{kind: tokenOr, text: word, position: start}, nil
+	case "and":
+		return token{kind: tokenAnd, text: word, position: start}, nil
+	}
+
+	return token{kind: tokenWord, text: word, position: start, value: word}, nil
+}
@@ -0,0 +1,231 @@
+package query
+
+import (
+	"strconv"
+)
+
+/*
+SyntaxError - Returned when a query cannot be parsed or compiled. Position is the position of the
+offending token in the query, counted in runes from zero, and Token is its raw text
+*/
+type SyntaxError struct {
+	Position int
+	Token    string
+	Message  string
+}
+
+func (err *SyntaxError) Error() string {
+	if err.Token == "" {
+		return "query: " + err.Message + " at position " + strconv.Itoa(err.Position)
+	}
+
+	return "query: " + err.Message + " at position " + strconv.Itoa(err.Position) + " ('" + err.Token + "')"
+}
+
+/*
+parser - A recursive descent parser over the tokens of a query. The grammar is:
+
+	expr    = and { "or" and }
+	and     = unary { [ "and" ] unary }
+	unary   = "-" unary | primary
+	primary = "(" expr ")" | "!" ( word | string ) | comparison | word | string
+
+Terms that are separated only by whitespace are joined with "and", which binds tighter than "or"
+*/
+type parser struct {
+	lexer   *lexer
+	current token
+}
+
+/*
+advance Move the parser to the next token of the query
+*/
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.current = tok
+	return nil
+}
+
+/*
+unexpected Return a SyntaxError for the current token
+*/
+func (p *parser) unexpected(message string) error {
+	return &SyntaxError{Position: p.current.position, Token: p.current.text, Message: message}
+}
+
+/*
+parseOr Parse one or more and expressions separated by "or"
+*/
+func (p *parser) parseOr() (Node, error) {
+	start := p.current.position
+
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{first}
+	for p.current.kind == tokenOr {
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+
+	return &OrNode{Children: children, Pos: start}, nil
+}
+
+/*
+parseAnd Parse one or more unary expressions, optionally separated by "and"
+*/
+func (p *parser) parseAnd() (Node, error) {
+	start := p.current.position
+
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{first}
+	for {
+		if p.current.kind == tokenAnd {
+			err = p.advance()
+			if err != nil {
+				return nil, err
+			}
+		} else if p.current.kind == tokenOr || p.current.kind == tokenRParen || p.current.kind == tokenEOF {
+			break
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+
+	return &AndNode{Children: children, Pos: start}, nil
+}
+
+/*
+parseUnary Parse an optionally negated primary expression
+*/
+func (p *parser) parseUnary() (Node, error) {
+	if p.current.kind != tokenMinus {
+		return p.parsePrimary()
+	}
+
+	start := p.current.position
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	child, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotNode{Child: child, Pos: start}, nil
+}
+
+/*
+parsePrimary Parse a parenthesized expression, exact name, comparison, or bare name
+*/
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.current
+
+	switch tok.kind {
+	case tokenLParen:
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.current.kind == tokenRParen {
+			return nil, p.unexpected("expected a search term inside parentheses")
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.current.kind != tokenRParen {
+			return nil, &SyntaxError{Position: tok.position, Token: tok.text, Message: "unclosed parenthesis"}
+		}
+
+		return node, p.advance()
+	case tokenBang:
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.current.kind != tokenWord && p.current.kind != tokenString {
+			return nil, p.unexpected("expected a card name after '!'")
+		}
+
+		node := &NameNode{Value: p.current.value, Exact: true, Pos: tok.position}
+		return node, p.advance()
+	case tokenComparison:
+		node := &ComparisonNode{Key: tok.key, Op: tok.op, Value: tok.value, Pos: tok.position, ValuePos: tok.valuePosition}
+		return node, p.advance()
+	case tokenWord, tokenString:
+		node := &NameNode{Value: tok.value, Pos: tok.position}
+		return node, p.advance()
+	case tokenRParen:
+		return nil, p.unexpected("unexpected closing parenthesis")
+	case tokenEOF:
+		return nil, p.unexpected("expected a search term")
+	}
+
+	return nil, p.unexpected("expected a search term")
+}
+
+/*
+Parse Parse a query into its AST. A *SyntaxError is returned if the query is malformed
+*/
+func Parse(input string) (Node, error) {
+	p := &parser{lexer: &lexer{input: []rune(input)}}
+
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.current.kind == tokenEOF {
+		return nil, p.unexpected("query is empty")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.current.kind != tokenEOF {
+		return nil, p.unexpected("unexpected closing parenthesis")
+	}
+
+	return node, nil
+}
@@ -0,0 +1,97 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"bolt", `"bolt"`},
+		{`!"Lightning Bolt"`, `!"Lightning Bolt"`},
+		{"t:goblin c>=rg", `(t:"goblin" and c>="rg")`},
+		{"t:goblin and c>=rg", `(t:"goblin" and c>="rg")`},
+		{"t:elf or t:goblin mv<=2", `(t:"elf" or (t:"goblin" and mv<="2"))`},
+		{"(t:elf or t:goblin) mv<=2", `((t:"elf" or t:"goblin") and mv<="2")`},
+		{"-t:land", `-t:"land"`},
+		{"--t:land", `--t:"land"`},
+		{`o:"draw a card"`, `o:"draw a card"`},
+		{`name:"say \"hi\""`, `name:"say \"hi\""`},
+		{"MV>=3", `mv>="3"`},
+		{"bolt OR shock", `("bolt" or "shock")`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			node, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an error: %v", test.input, err)
+			}
+
+			if got := node.String(); got != test.want {
+				t.Errorf("Parse(%q) = %s, want %s", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+		token    string
+		message  string
+	}{
+		{"", 0, "", "query is empty"},
+		{"   ", 3, "", "query is empty"},
+		{"(t:goblin", 0, "(", "unclosed parenthesis"},
+		{"t:goblin)", 8, ")", "unexpected closing parenthesis"},
+		{")", 0, ")", "unexpected closing parenthesis"},
+		{"()", 1, ")", "expected a search term inside parentheses"},
+		{`name:"bolt`, 5, `"bolt`, "unterminated quoted string"},
+		{"mv<=", 0, "mv<=", "expected a value after '<='"},
+		{"!", 1, "", "expected a card name after '!'"},
+		{"t:elf and", 9, "", "expected a search term"},
+		{"t:elf or", 8, "", "expected a search term"},
+		{"-", 1, "", "expected a search term"},
+		{`"Æther" (`, 9, "", "expected a search term"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := Parse(test.input)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) returned %v, want a *SyntaxError", test.input, err)
+			}
+
+			if syntaxErr.Position != test.position {
+				t.Errorf("Position = %d, want %d", syntaxErr.Position, test.position)
+			}
+
+			if syntaxErr.Token != test.token {
+				t.Errorf("Token = %q, want %q", syntaxErr.Token, test.token)
+			}
+
+			if syntaxErr.Message != test.message {
+				t.Errorf("Message = %q, want %q", syntaxErr.Message, test.message)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorString(t *testing.T) {
+	err := &SyntaxError{Position: 4, Token: "x", Message: "mana value must be a number"}
+	if got, want := err.Error(), "query: mana value must be a number at position 4 ('x')"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	err = &SyntaxError{Position: 0, Message: "query is empty"}
+	if got, want := err.Error(), "query: query is empty at position 0"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}